@@ -0,0 +1,119 @@
+package esaMatcher
+
+import (
+	"fmt"
+	"sort"
+)
+
+// An Alphabet describes the symbols a text may consist of.
+//
+// It validates input texts, maps each symbol to its rank and decides how the texts are
+// terminated. If all symbols of a text sort above the sentinels '#' and '$', the sentinels
+// are appended to the text as they are. Otherwise, for example for binary data over the
+// Bytes alphabet, the text is encoded by the ranks of its symbols and the sentinels are
+// stored out-of-band, that is as unique values that sort lower than any symbol.
+type Alphabet struct {
+	name    string
+	symbols []byte
+	ranks   [256]int
+}
+
+// Predefined alphabets.
+var (
+	// Nucleotides of DNA, including N for unknown nucleotides.
+	DNA = mustAlphabet("DNA", "ACGTN")
+	// Nucleotides of DNA including all IUPAC ambiguity codes.
+	IUPAC = mustAlphabet("IUPAC", "ACGTRYKMSWBDHVN")
+	// Nucleotides of RNA, including N for unknown nucleotides.
+	RNA = mustAlphabet("RNA", "ACGUN")
+	// The one letter codes of amino acids including ambiguity codes and the stop codon.
+	Protein = mustAlphabet("Protein", "ACDEFGHIKLMNPQRSTVWYBZXUO*")
+	// All bytes, the alphabet for arbitrary binary data.
+	Bytes = mustAlphabet("Bytes", allBytes())
+)
+
+// NewAlphabet returns a custom alphabet with the given name that consists of the given symbols.
+// The order of symbols does not matter, but each symbol must only occur once.
+func NewAlphabet(name string, symbols string) (*Alphabet, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("alphabet %s has no symbols", name)
+	}
+	a := &Alphabet{name: name, symbols: []byte(symbols)}
+	sort.Slice(a.symbols, func(i, j int) bool { return a.symbols[i] < a.symbols[j] })
+	for i := range a.ranks {
+		a.ranks[i] = -1
+	}
+	for r, c := range a.symbols {
+		if a.ranks[c] != -1 {
+			return nil, fmt.Errorf("symbol %q occurs twice in alphabet %s", c, name)
+		}
+		a.ranks[c] = r
+	}
+	return a, nil
+}
+
+func mustAlphabet(name string, symbols string) *Alphabet {
+	a, err := NewAlphabet(name, symbols)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func allBytes() string {
+	b := make([]byte, 256)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return string(b)
+}
+
+// Return the name of the alphabet.
+func (a *Alphabet) Name() string { return a.name }
+
+// Return the number of symbols in the alphabet.
+func (a *Alphabet) Size() int { return len(a.symbols) }
+
+// Return the symbols of the alphabet in ascending order.
+func (a *Alphabet) Symbols() []byte { return a.symbols }
+
+// Contains reports whether c is a symbol of the alphabet.
+func (a *Alphabet) Contains(c byte) bool { return a.ranks[c] >= 0 }
+
+// Rank returns the rank of c in the alphabet, that is the number of smaller symbols.
+// If c is not part of the alphabet, -1 is returned.
+func (a *Alphabet) Rank(c byte) int { return a.ranks[c] }
+
+// Validate returns an error naming the first position of s that holds a symbol
+// not contained in the alphabet, or nil if s is a valid text.
+func (a *Alphabet) Validate(s []byte) error {
+	for i, c := range s {
+		if a.ranks[c] < 0 {
+			return fmt.Errorf("symbol %q at position %d is not in alphabet %s", c, i, a.name)
+		}
+	}
+	return nil
+}
+
+// Sentinels returns the bytes that separate the strands and terminate the text.
+// If ok is false, the alphabet contains bytes that do not sort above the sentinels
+// and texts containing those bytes are stored with out-of-band sentinels.
+func (a *Alphabet) Sentinels() (sep, end byte, ok bool) {
+	return '#', '$', a.symbols[0] > '$'
+}
+
+// Encode the text t, whose strands are terminated at strandSize and at its last position,
+// by the ranks of its symbols. The sentinels are encoded as 0 and 1 and all symbols are
+// shifted by two, such that the encoded text has an alphabet of a.Size()+2 values.
+func (a *Alphabet) encode(t []byte, strandSize int) []int32 {
+	n := len(t)
+	enc := make([]int32, n)
+	for i, c := range t[:n-1] {
+		enc[i] = int32(a.ranks[c] + 2)
+	}
+	if strandSize < n-1 {
+		enc[strandSize] = 0
+	}
+	enc[n-1] = 1
+	return enc
+}
@@ -0,0 +1,123 @@
+package esaMatcher
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAlphabet(t *testing.T) {
+	if DNA.Size() != 5 || DNA.Rank('A') != 0 || DNA.Rank('T') != 4 || DNA.Rank('U') != -1 {
+		t.Errorf("Unexpected ranks for DNA alphabet %q", DNA.Symbols())
+	}
+	if err := DNA.Validate([]byte("ACGTN")); err != nil {
+		t.Error(err)
+	}
+	if err := DNA.Validate([]byte("ACGUN")); err == nil {
+		t.Error("Validate accepted U as DNA")
+	}
+	if err := Bytes.Validate([]byte("\x00\xff$#")); err != nil {
+		t.Error(err)
+	}
+	if _, err := NewAlphabet("dup", "ABA"); err == nil {
+		t.Error("NewAlphabet accepted duplicate symbols")
+	}
+	if _, _, ok := Protein.Sentinels(); !ok {
+		t.Error("Protein alphabet should use the byte sentinels")
+	}
+	if _, _, ok := Bytes.Sentinels(); ok {
+		t.Error("Bytes alphabet must not use the byte sentinels")
+	}
+	if _, err := NewAlphabetEsa([]byte("ACGU"), DNA, ""); err == nil {
+		t.Error("NewAlphabetEsa accepted invalid text")
+	}
+}
+
+func TestNewAlphabetEsa(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("ACTTCACAAA"), //ranseq
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		[]byte("TCTAATGAATATGTAGGATACGAATCGGATGATTCTCGAGCATCAGCATGGA" +
+			"GGACTCCATGTGTTAAGTCACCGACTGCGTGCCACCTGCGTCTTCGAAACGG"),
+	}
+	// The same text must give the same ESA, regardless of in-band or out-of-band sentinels.
+	for _, seq := range seqs {
+		e := NewEsa(seq, "")
+		for _, a := range []*Alphabet{DNA, Bytes} {
+			ae, err := NewAlphabetEsa(seq, a, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range e.sa {
+				if e.sa[i] != ae.sa[i] || e.lcp[i] != ae.lcp[i] || e.cld[i] != ae.cld[i] {
+					t.Errorf("ESA over %s differs at %d for %s", a.Name(), i, seq)
+					break
+				}
+			}
+		}
+	}
+
+	texts := []struct {
+		a *Alphabet
+		s []byte
+	}{
+		{Protein, []byte("MKVLAAGIVALLLAAGCSSSKEETAQ*MKVLA")},
+		{Bytes, []byte("a$b#c\x00a$b\xffa$b#")},
+		{Bytes, ranseq(2000, "\x00#$\xff")},
+	}
+	for _, text := range texts {
+		e, err := NewAlphabetEsa(text.s, text.a, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkOwnSuffixes(t, &e, text.s, 0)
+	}
+}
+
+func TestEsa16(t *testing.T) {
+	s := make([]uint16, 3000)
+	for i := range s {
+		s[i] = uint16(rand.Intn(300) * 200)
+	}
+	e := NewEsa16(s, "")
+	for i := range s {
+		m := e.GetMatch(s[i:])
+		if m.L() != len(s)-i {
+			t.Fatalf("Match of suffix %d has length %d, want %d", i, m.L(), len(s)-i)
+		}
+		found := false
+		for k := m.Start(); k <= m.End(); k++ {
+			found = found || e.sa[k] == i
+		}
+		if !found {
+			t.Errorf("Suffix %d is not in its match interval", i)
+		}
+	}
+	if m := e.GetMatch([]uint16{1}); m.Start() != -1 {
+		t.Errorf("Found match for missing symbol")
+	}
+	if got := e.Sequence(); len(got) != len(s) || got[17] != s[17] {
+		t.Errorf("Sequence does not return the text")
+	}
+}
+
+// Check that every suffix of s is found with its full length in the ESA e
+// that contains s at the given offset.
+func checkOwnSuffixes(t *testing.T, e *Esa, s []byte, offset int) {
+	t.Helper()
+	for i := range s {
+		m := e.GetMatch(s[i:])
+		if m.L() != len(s)-i {
+			t.Errorf("Match of suffix %d has length %d, want %d", i, m.L(), len(s)-i)
+			return
+		}
+		found := false
+		for k := m.Start(); k <= m.End(); k++ {
+			found = found || e.sa[k] == offset+i
+		}
+		if !found {
+			t.Errorf("Suffix %d is not in its match interval", i)
+			return
+		}
+	}
+}
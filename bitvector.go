@@ -0,0 +1,116 @@
+package esaMatcher
+
+import "math/bits"
+
+// Number of words per superblock of the rank directory of a bitVector.
+const bitSuperWords = 1 << 16
+
+// A bitVector is a fixed size array of bits that answers rank queries in O(1)
+// and select queries in O(log n) time.
+type bitVector struct {
+	words []uint64
+	// Number of set bits before each superblock and, relative to its superblock,
+	// before each word.
+	supers []uint64
+	ranks  []uint32
+}
+
+func newBitVector(n int) *bitVector {
+	return &bitVector{words: make([]uint64, (n+63)/64)}
+}
+
+func (b *bitVector) set(i int) { b.words[i/64] |= 1 << (i % 64) }
+
+func (b *bitVector) get(i int) bool { return b.words[i/64]&(1<<(i%64)) != 0 }
+
+// Compute the rank directory. It must be called after the last call of set.
+func (b *bitVector) build() {
+	b.ranks = make([]uint32, len(b.words)+1)
+	b.supers = make([]uint64, len(b.words)/bitSuperWords+1)
+	var r uint64
+	for w := 0; w <= len(b.words); w++ {
+		if w%bitSuperWords == 0 {
+			b.supers[w/bitSuperWords] = r
+		}
+		b.ranks[w] = uint32(r - b.supers[w/bitSuperWords])
+		if w < len(b.words) {
+			r += uint64(bits.OnesCount64(b.words[w]))
+		}
+	}
+}
+
+// Return the number of set bits before word w.
+func (b *bitVector) wordRank(w int) int {
+	return int(b.supers[w/bitSuperWords] + uint64(b.ranks[w]))
+}
+
+// Return the number of set bits before position i.
+func (b *bitVector) rank(i int) int {
+	w := i / 64
+	r := b.wordRank(w)
+	if i%64 != 0 {
+		r += bits.OnesCount64(b.words[w] & (1<<(i%64) - 1))
+	}
+	return r
+}
+
+// Return the position of the k-th set bit, counting from 0.
+func (b *bitVector) select1(k int) int {
+	// Find the last word with less than k+1 set bits before it.
+	lo, hi := 0, len(b.words)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if b.wordRank(mid) <= k {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	x := b.words[lo]
+	for k -= b.wordRank(lo); k > 0; k-- {
+		x &= x - 1
+	}
+	return lo*64 + bits.TrailingZeros64(x)
+}
+
+// Return the size of the bit vector in bytes.
+func (b *bitVector) size() int { return 8*len(b.words) + 8*len(b.supers) + 4*len(b.ranks) }
+
+// An intVector is a fixed size array of unsigned integers that are packed into
+// words of the width of their largest value.
+type intVector struct {
+	width int
+	words []uint64
+}
+
+// Return an intVector of n integers from 0 to max.
+func newIntVector(n, max int) *intVector {
+	width := bits.Len(uint(max))
+	if width == 0 {
+		width = 1
+	}
+	return &intVector{width, make([]uint64, (n*width+63)/64)}
+}
+
+// Set the integer at index i to x. Each index must be set once at most.
+func (v *intVector) set(i, x int) {
+	p := i * v.width
+	w, off := p/64, p%64
+	v.words[w] |= uint64(x) << off
+	if off+v.width > 64 {
+		v.words[w+1] |= uint64(x) >> (64 - off)
+	}
+}
+
+func (v *intVector) get(i int) int {
+	p := i * v.width
+	w, off := p/64, p%64
+	x := v.words[w] >> off
+	if off+v.width > 64 {
+		x |= v.words[w+1] << (64 - off)
+	}
+	return int(x & (1<<v.width - 1))
+}
+
+// Return the size of the vector in bytes.
+func (v *intVector) size() int { return 8 * len(v.words) }
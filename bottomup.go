@@ -0,0 +1,71 @@
+package esaMatcher
+
+// BottomUp traverses the lcp-interval tree of e bottom-up and calls visit for every
+// lcp-interval after all of its child intervals. Singleton intervals are not visited.
+// The interval passed to visit holds its lcp value in L(), its left and right bound
+// in Start() and End() and its first child boundary in Mid().
+// The children slice holds the values returned by visit for the child intervals, in the
+// order of the suffix array. It is only valid during the call and must not be kept.
+// BottomUp returns the value of the root interval.
+//
+// As an example, the number of lcp-intervals in the subtree of every interval is
+// accumulated by
+//
+// 	BottomUp(&e, func(iv EsaInterval, children []int) int {
+// 		sum := 1
+// 		for _, c := range children {
+// 			sum += c
+// 		}
+// 		return sum
+// 	})
+//
+// Implementation
+//
+// This is algorithm 4.4 from Ohlebusch - Bioinformatics Algorithms (2013). The intervals
+// that are not yet closed are kept on a stack. Position i opens a new interval if lcp[i]
+// is larger than the lcp value on top of the stack and closes all intervals with a larger
+// lcp value. The values of the children of all open intervals share a second stack.
+func BottomUp[V any](e *Esa, visit func(iv EsaInterval, children []V) V) V {
+	type frame struct {
+		iv    EsaInterval
+		first int
+	}
+	stack := []frame{{EsaInterval{0, -1, 0, 0}, 0}}
+	var values []V
+	var root V
+	for i := 1; i <= len(e.sa); i++ {
+		lb := i - 1
+		var last V
+		closed := false
+		for len(stack) > 0 && e.lcp[i] < stack[len(stack)-1].iv.l {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			top.iv.end = i - 1
+			last = visit(top.iv, values[top.first:])
+			values = values[:top.first]
+			lb = top.iv.start
+			closed = true
+			if len(stack) == 0 {
+				root = last
+			} else if e.lcp[i] <= stack[len(stack)-1].iv.l {
+				values = append(values, last)
+				closed = false
+			}
+		}
+		if len(stack) == 0 {
+			break
+		}
+		top := &stack[len(stack)-1]
+		switch {
+		case e.lcp[i] == top.iv.l && top.iv.mid == top.iv.start:
+			top.iv.mid = i
+		case e.lcp[i] > top.iv.l:
+			f := frame{EsaInterval{lb, -1, i, e.lcp[i]}, len(values)}
+			if closed {
+				values = append(values, last)
+			}
+			stack = append(stack, f)
+		}
+	}
+	return root
+}
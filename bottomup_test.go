@@ -0,0 +1,60 @@
+package esaMatcher
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBottomUp(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		[]byte("A$C#AC$"),
+		ranseq(500, "ACG"),
+	}
+	for _, s := range seqs {
+		for _, e := range []Esa{NewEsa(s, ""), NewRevEsa(s, "")} {
+			// Collect the intervals top-down for comparison.
+			want := map[string]bool{}
+			var topDown func(iv EsaInterval)
+			topDown = func(iv EsaInterval) {
+				want[fmt.Sprint(iv)] = true
+				e.eachChild(iv, func(c EsaInterval) {
+					if c.start != c.end {
+						topDown(c)
+					}
+				})
+			}
+			topDown(e.Root())
+
+			got := map[string]bool{}
+			n := BottomUp(&e, func(iv EsaInterval, children []int) int {
+				if iv != e.Interval(iv.start, iv.end) {
+					t.Errorf("visited %v, want %v", iv, e.Interval(iv.start, iv.end))
+				}
+				got[fmt.Sprint(iv)] = true
+				// Count the leaves of the subtree.
+				leaves := iv.end - iv.start + 1
+				sum := 0
+				e.eachChild(iv, func(c EsaInterval) {
+					if c.start == c.end {
+						sum++
+					}
+				})
+				for _, c := range children {
+					sum += c
+				}
+				if sum != leaves {
+					t.Errorf("%v has %d leaves in its children, want %d", iv, sum, leaves)
+				}
+				return sum
+			})
+			if n != len(e.Sa()) {
+				t.Errorf("root has %d leaves, want %d", n, len(e.Sa()))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("BottomUp visited %v, want %v", got, want)
+			}
+		}
+	}
+}
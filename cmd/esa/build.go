@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	esa "github.com/dadidange/esaMatcher"
+)
+
+// Build the ESA of FASTA files and write it to disk.
+func runBuild(args []string) error {
+	fs := newFlagSet("build", "<fasta>...")
+	out := fs.String("o", "", "write the index to `file` (default: first input with extension .esa)")
+	saLib := fs.String("sa", "", "suffix array `library`: SaSais, SaDivSufSort or SaNaive (default SaSais)")
+	rev := fs.Bool("rev", false, "include the reverse complement")
+	upper := fs.Bool("upper", false, "convert soft-masked (lowercase) nucleotides to uppercase")
+	hardMask := fs.Bool("hardmask", false, "replace soft-masked (lowercase) nucleotides by N")
+	quiet := fs.Bool("q", false, "do not report time and memory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no FASTA file given")
+	}
+	if err := checkSaLib(*saLib); err != nil {
+		return err
+	}
+	if *out == "" {
+		if fs.Arg(0) == "-" {
+			return errors.New("the index file must be given with -o when reading from the standard input")
+		}
+		*out = strings.TrimSuffix(strings.TrimSuffix(fs.Arg(0), ".gz"), ".fasta")
+		*out = strings.TrimSuffix(*out, ".fa") + ".esa"
+	}
+
+	sw := newStopwatch(*quiet)
+	records, err := readFastaFiles(fs.Args(), esa.FastaOptions{Upper: *upper, HardMask: *hardMask})
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return errors.New("no FASTA records found")
+	}
+	n := 0
+	for _, r := range records {
+		n += len(r.Seq)
+	}
+	sw.step("read %d records, %d bp", len(records), n)
+
+	e, m := esa.NewFastaEsa(records, *rev, *saLib)
+	sw.step("built ESA of %d suffixes", len(e.Sa()))
+
+	size, err := writeIndex(*out, &index{e, m})
+	if err != nil {
+		return err
+	}
+	sw.step("wrote %s, %d bytes", *out, size)
+	sw.total()
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	esa "github.com/dadidange/esaMatcher"
+)
+
+// Print the summary statistics of an index.
+func runStats(args []string) error {
+	fs := newFlagSet("stats", "<index>")
+	asJSON := fs.Bool("json", false, "write JSON instead of text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("exactly one index is required")
+	}
+	idx, err := readIndex(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	st := idx.esa.Stats()
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+	if *asJSON {
+		// Symbols are written as strings, since JSON keys of bytes would be numbers.
+		comp := map[string]int{}
+		for c, n := range st.Composition {
+			comp[string(c)] = n
+		}
+		return json.NewEncoder(w).Encode(struct {
+			Records            int            `json:"records"`
+			Length             int            `json:"length"`
+			StrandSize         int            `json:"strandSize"`
+			Alphabet           string         `json:"alphabet"`
+			Composition        map[string]int `json:"composition"`
+			MaxLcp             int            `json:"maxLcp"`
+			MeanLcp            float64        `json:"meanLcp"`
+			Intervals          int            `json:"intervals"`
+			DistinctSubstrings int            `json:"distinctSubstrings"`
+		}{len(idx.records.Names()), st.Length, st.StrandSize, st.Alphabet, comp,
+			st.MaxLcp, st.MeanLcp, st.Intervals, st.DistinctSubstrings})
+	}
+	fmt.Fprintf(w, "records\t%d\n", len(idx.records.Names()))
+	fmt.Fprintf(w, "length\t%d\n", st.Length)
+	fmt.Fprintf(w, "strand size\t%d\n", st.StrandSize)
+	fmt.Fprintf(w, "alphabet\t%s\n", st.Alphabet)
+	fmt.Fprintf(w, "max lcp\t%d\n", st.MaxLcp)
+	fmt.Fprintf(w, "mean lcp\t%.2f\n", st.MeanLcp)
+	fmt.Fprintf(w, "lcp-intervals\t%d\n", st.Intervals)
+	fmt.Fprintf(w, "distinct substrings\t%d\n", st.DistinctSubstrings)
+	symbols := make([]int, 0, len(st.Composition))
+	total := 0
+	for c, n := range st.Composition {
+		symbols = append(symbols, int(c))
+		total += n
+	}
+	sort.Ints(symbols)
+	for _, c := range symbols {
+		n := st.Composition[byte(c)]
+		fmt.Fprintf(w, "symbol %q\t%d\t%.2f%%\n", c, n, 100*float64(n)/float64(total))
+	}
+	return nil
+}
+
+// Print rows of the arrays of an index.
+func runPrint(args []string) error {
+	fs := newFlagSet("print", "<index>")
+	from := fs.Int("from", 0, "first `row` to print")
+	to := fs.Int("to", 20, "print the rows before `row`, 0 for all")
+	width := fs.Int("width", 40, "truncate the suffixes to `n` characters, 0 for no limit")
+	columns := fs.String("columns", "sa,lcp,cld,suffix", "comma separated `list` of the columns sa, isa, lcp, cld, bwt and suffix")
+	format := fs.String("format", "tsv", "output format: tsv, markdown or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("exactly one index is required")
+	}
+	opts := esa.TableOptions{From: *from, To: *to, Width: *width}
+	for _, c := range strings.Split(*columns, ",") {
+		col, ok := tableColumns[c]
+		if !ok {
+			return fmt.Errorf("unknown column %q", c)
+		}
+		opts.Columns |= col
+	}
+	var ok bool
+	if opts.Format, ok = tableFormats[*format]; !ok {
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+	idx, err := readIndex(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return idx.esa.WriteTable(stdout, opts)
+}
+
+var tableColumns = map[string]esa.TableColumn{
+	"sa": esa.ColSA, "isa": esa.ColISA, "lcp": esa.ColLCP,
+	"cld": esa.ColCLD, "bwt": esa.ColBWT, "suffix": esa.ColSuffix,
+}
+
+var tableFormats = map[string]esa.TableFormat{
+	"tsv": esa.TableTSV, "markdown": esa.TableMarkdown, "json": esa.TableJSON,
+}
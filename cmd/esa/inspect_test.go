@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	idx := buildIndex(t, t.TempDir())
+	out := runOutput(t, runStats, idx)
+	for _, want := range []string{"records\t2\n", "length\t32\n", "strand size\t15\n", "symbol 'A'\t7\t25.00%\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("stats output %q does not contain %q", out, want)
+		}
+	}
+	// The record separators are sentinels and not part of the composition.
+	if strings.Contains(out, "symbol '%'") {
+		t.Errorf("stats output %q counts the record separators", out)
+	}
+	var st struct {
+		Records     int            `json:"records"`
+		Composition map[string]int `json:"composition"`
+	}
+	if err := json.Unmarshal([]byte(runOutput(t, runStats, "-json", idx)), &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Records != 2 || st.Composition["A"] != 7 {
+		t.Errorf("stats -json = %+v", st)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	idx := buildIndex(t, t.TempDir())
+	out := runOutput(t, runPrint, "-from", "0", "-to", "3", "-width", "5", idx)
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 4 || lines[0] != "i\tSA\tLCP\tCLD\tS[SA[i]..]" {
+		t.Fatalf("print output = %q", out)
+	}
+	// The sentinel '#' sorts first, followed by '$'.
+	if !strings.HasPrefix(lines[1], "0\t15\t-1\t") || !strings.HasSuffix(lines[1], "\t#AACC...") {
+		t.Errorf("row 0 = %q", lines[1])
+	}
+	if !strings.HasSuffix(lines[2], "\t$") {
+		t.Errorf("row 1 = %q", lines[2])
+	}
+	out = runOutput(t, runPrint, "-to", "2", "-columns", "isa,bwt", "-format", "markdown", idx)
+	if want := "| i | ISA | BWT |\n|---|---|---|\n| 0 | 10 | T |\n| 1 | 17 | T |\n"; out != want {
+		t.Errorf("print -format markdown = %q, want %q", out, want)
+	}
+	for _, args := range [][]string{
+		{"-from", "5", "-to", "3", idx},
+		{"-columns", "sa,foo", idx},
+		{"-format", "xml", idx},
+	} {
+		if err := runPrint(args); err == nil {
+			t.Errorf("print %v did not fail", args)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Command esa builds enhanced suffix arrays of FASTA files and queries them.
+//
+// Usage
+//
+// 	esa <command> [flags] [arguments]
+//
+// The commands are
+//
+// 	build   index FASTA files into a serialized ESA
+// 	query   match patterns against an index
+// 	mum     report maximal unique matches between a reference and queries
+// 	mem     report maximal exact matches between a reference and queries
+// 	stats   print summary statistics of an index
+// 	print   print rows of the arrays of an index
+// 	serve   answer queries against an index over HTTP
+//
+// Run esa <command> -h for the flags of a command.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	esa "github.com/dadidange/esaMatcher"
+)
+
+// A command is a subcommand of esa.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+// The output of the commands, replaced in tests.
+var stdout io.Writer = os.Stdout
+
+var commands = []command{
+	{"build", "index FASTA files into a serialized ESA", runBuild},
+	{"query", "match patterns against an index", runQuery},
+	{"mum", "report maximal unique matches between a reference and queries", runMum},
+	{"mem", "report maximal exact matches between a reference and queries", runMem},
+	{"stats", "print summary statistics of an index", runStats},
+	{"print", "print rows of the arrays of an index", runPrint},
+	{"serve", "answer queries against an index over HTTP", runServe},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			if err := c.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "esa %s: %v\n", c.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	if os.Args[1] != "-h" && os.Args[1] != "help" {
+		fmt.Fprintf(os.Stderr, "esa: unknown command %q\n", os.Args[1])
+	}
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: esa <command> [flags] [arguments]\n\nThe commands are:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "\t%-8s%s\n", c.name, c.usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun esa <command> -h for the flags of a command.\n")
+}
+
+// Return a flag set for the command with the given name and argument synopsis.
+func newFlagSet(name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet("esa "+name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: esa %s [flags] %s\n\nFlags:\n", name, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// Check that lib names a suffix array library known to esa.Sa.
+func checkSaLib(lib string) error {
+	switch lib {
+	case "", "SaSais", "SaDivSufSort", "SaNaive":
+		return nil
+	}
+	return fmt.Errorf("unknown suffix array library %q, use SaSais, SaDivSufSort or SaNaive", lib)
+}
+
+// An index is the ESA of a set of FASTA records together with the record coordinates.
+type index struct {
+	esa     esa.Esa
+	records *esa.RecordMap
+}
+
+// Write the index to the file at path.
+func writeIndex(path string, idx *index) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	n, err := esa.WriteIndex(f, &idx.esa, idx.records)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return n, err
+}
+
+// Read the index from the file at path.
+func readIndex(path string) (*index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	e, m, err := esa.ReadIndex(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &index{e, m}, nil
+}
+
+// Open the file at path for reading, "-" is the standard input.
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// Read the records of all FASTA files.
+func readFastaFiles(paths []string, opts esa.FastaOptions) ([]esa.FastaRecord, error) {
+	var records []esa.FastaRecord
+	for _, path := range paths {
+		f, err := openInput(path)
+		if err != nil {
+			return nil, err
+		}
+		r, err := esa.ReadFasta(f, opts)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		records = append(records, r...)
+	}
+	return records, nil
+}
+
+// A stopwatch reports the time and memory used by the steps of a command to the standard error.
+type stopwatch struct {
+	quiet bool
+	start time.Time
+	last  time.Time
+}
+
+func newStopwatch(quiet bool) *stopwatch {
+	now := time.Now()
+	return &stopwatch{quiet, now, now}
+}
+
+// Report the time since the last step and the memory obtained from the OS so far.
+func (s *stopwatch) step(format string, args ...interface{}) {
+	now := time.Now()
+	if !s.quiet {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+		fmt.Fprintf(os.Stderr, "%-40s %10s %8.1f MiB\n", fmt.Sprintf(format, args...),
+			now.Sub(s.last).Round(time.Millisecond), float64(ms.Sys)/(1<<20))
+	}
+	s.last = now
+}
+
+// Report the total time.
+func (s *stopwatch) total() {
+	if !s.quiet {
+		fmt.Fprintf(os.Stderr, "%-40s %10s\n", "total", time.Since(s.start).Round(time.Millisecond))
+	}
+}
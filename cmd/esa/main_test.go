@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Write a FASTA file into dir and return its path.
+func writeFasta(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	fasta := writeFasta(t, dir, "ref.fasta", ">one\nACGTACGT\nAC\n>two\nggtt\n")
+	if err := runBuild([]string{"-q", "-rev", "-sa", "SaNaive", fasta}); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := readIndex(filepath.Join(dir, "ref.esa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(idx.esa.Sequence()[:15]), "ACGTACGTAC%ggtt"; got != want {
+		t.Errorf("sequence = %q, want %q", got, want)
+	}
+	if idx.esa.StrandSize() != 15 {
+		t.Errorf("strand size = %d, want 15", idx.esa.StrandSize())
+	}
+	if names := idx.records.Names(); len(names) != 2 || names[1] != "two" {
+		t.Errorf("records = %v, want [one two]", names)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"-sa", "SaFoo", fasta},
+		{"-q", filepath.Join(dir, "missing.fasta")},
+		{"-q", "-"},
+	} {
+		if err := runBuild(args); err == nil {
+			t.Errorf("build %v did not fail", args)
+		}
+	}
+}
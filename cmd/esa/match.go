@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	esa "github.com/dadidange/esaMatcher"
+)
+
+// Report maximal unique matches between a reference and query sequences.
+func runMum(args []string) error { return runMatches("mum", args) }
+
+// Report maximal exact matches between a reference and query sequences.
+func runMem(args []string) error { return runMatches("mem", args) }
+
+// A match is a MEM or MUM between a query record and a reference record.
+type match struct {
+	queryPos  int
+	refRecord int
+	refPos    int
+	length    int
+}
+
+// Find the MUMs or MEMs, depending on mode, of the query records against a reference.
+func runMatches(mode string, args []string) error {
+	fs := newFlagSet(mode, "<reference> <query>...")
+	minLength := fs.Int("l", 20, "minimum match `length`")
+	both := fs.Bool("b", false, "also match the reverse complement of the queries")
+	format := fs.String("format", "mummer", "output format: mummer or paf")
+	saLib := fs.String("sa", "", "suffix array `library` for FASTA references: SaSais, SaDivSufSort or SaNaive")
+	upper := fs.Bool("upper", false, "convert soft-masked (lowercase) nucleotides to uppercase")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return errors.New("reference and query are required")
+	}
+	if *format != "mummer" && *format != "paf" {
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+	if err := checkSaLib(*saLib); err != nil {
+		return err
+	}
+	opts := esa.FastaOptions{Upper: *upper}
+	ref, err := loadReference(fs.Arg(0), opts, *saLib)
+	if err != nil {
+		return err
+	}
+	ref.esa = ref.esa.WithSuffixLinks()
+
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+	for _, path := range fs.Args()[1:] {
+		f, err := openInput(path)
+		if err != nil {
+			return err
+		}
+		fr, err := esa.NewFastaReader(f, opts)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		for {
+			rec, err := fr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				f.Close()
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			strands := []bool{false}
+			if *both {
+				strands = append(strands, true)
+			}
+			for _, reverse := range strands {
+				matches := findMatches(mode, ref, rec.Seq, reverse, *minLength)
+				if err := writeMatches(w, *format, ref, &rec, reverse, matches); err != nil {
+					f.Close()
+					return err
+				}
+			}
+		}
+		f.Close()
+	}
+	return nil
+}
+
+// Load the reference from a serialized index or build it from a FASTA file.
+// Indices that include the reverse complement are rejected, since matches to the
+// reverse strand are found by reversing the query instead.
+func loadReference(path string, opts esa.FastaOptions, saLib string) (*index, error) {
+	if strings.HasSuffix(path, ".esa") {
+		idx, err := readIndex(path)
+		if err != nil {
+			return nil, err
+		}
+		if e := &idx.esa; e.StrandSize() != len(e.Sequence())-1 {
+			return nil, fmt.Errorf("%s includes the reverse complement, build it without -rev and use -b", path)
+		}
+		return idx, nil
+	}
+	records, err := readFastaFiles([]string{path}, opts)
+	if err != nil {
+		return nil, err
+	}
+	e, m := esa.NewFastaEsa(records, false, saLib)
+	return &index{e, m}, nil
+}
+
+// Find the MUMs or MEMs of a query record or its reverse complement.
+func findMatches(mode string, ref *index, seq []byte, reverse bool, minLength int) []match {
+	if reverse {
+		seq = esa.RevComp(seq)
+	}
+	var mems []esa.MEM
+	if mode == "mum" {
+		mems = ref.esa.FindMUMs(seq, minLength)
+	} else {
+		mems = ref.esa.FindMEMs(seq, minLength)
+	}
+	matches := make([]match, 0, len(mems))
+	for _, m := range mems {
+		r, off, _ := ref.records.Locate(m.RefPos)
+		matches = append(matches, match{m.QueryPos, r, off, m.Length})
+	}
+	return matches
+}
+
+// Write the matches of a query record in the given format.
+//
+// The MUMmer format starts with a header line per query and strand, even without matches, followed by a line per match
+// with the reference name and the 1-based positions in the reference and the query.
+// For the reverse strand, the query positions are relative to the reverse complement.
+//
+// PAF has a line per match with 0-based, half-open intervals on the forward strands.
+func writeMatches(w io.Writer, format string, ref *index, query *esa.FastaRecord, reverse bool, matches []match) error {
+	if format == "mummer" {
+		header := "> " + query.Name
+		if reverse {
+			header += " Reverse"
+		}
+		if _, err := fmt.Fprintln(w, header); err != nil {
+			return err
+		}
+	}
+	names := ref.records.Names()
+	for _, m := range matches {
+		if m.refRecord < 0 {
+			// The match starts at a separator or sentinel and belongs to no record.
+			continue
+		}
+		var err error
+		if format == "mummer" {
+			_, err = fmt.Fprintf(w, "  %s\t%8d  %8d  %8d\n", names[m.refRecord], m.refPos+1, m.queryPos+1, m.length)
+		} else {
+			qlen := len(query.Seq)
+			qstart, strand := m.queryPos, '+'
+			if reverse {
+				qstart, strand = qlen-m.queryPos-m.length, '-'
+			}
+			_, err = fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%c\t%s\t%d\t%d\t%d\t%d\t%d\t255\tcg:Z:%dM\n",
+				query.Name, qlen, qstart, qstart+m.length, strand,
+				names[m.refRecord], ref.records.Len(m.refRecord), m.refPos, m.refPos+m.length,
+				m.length, m.length, m.length)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
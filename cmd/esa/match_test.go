@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	esa "github.com/dadidange/esaMatcher"
+)
+
+func TestMatches(t *testing.T) {
+	dir := t.TempDir()
+	ref := writeFasta(t, dir, "ref.fasta", ">r1\nTTTTACGTACGGATTT\n>r2\nCCCCAGATTACAGGGG\n")
+	query := writeFasta(t, dir, "query.fasta", ">q\nACGTACGGAGAGATTACA\n")
+	idx := filepath.Join(dir, "ref.esa")
+	if err := runBuild([]string{"-q", "-sa", "SaNaive", "-o", idx, ref}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		run  func([]string) error
+		args []string
+		want string
+	}{
+		{runMem, []string{"-l", "6", ref, query},
+			"> q\n" +
+				"  r1\t       5         1         9\n" +
+				"  r2\t       5        11         8\n"},
+		{runMum, []string{"-l", "6", "-sa", "SaNaive", idx, query},
+			"> q\n" +
+				"  r1\t       5         1         9\n" +
+				"  r2\t       5        11         8\n"},
+		{runMum, []string{"-l", "6", "-b", ref, query},
+			"> q\n" +
+				"  r1\t       5         1         9\n" +
+				"  r2\t       5        11         8\n" +
+				"> q Reverse\n" +
+				"  r1\t       6        12         6\n"},
+		{runMem, []string{"-l", "7", "-format", "paf", ref, query},
+			"q\t18\t0\t9\t+\tr1\t16\t4\t13\t9\t9\t255\tcg:Z:9M\n" +
+				"q\t18\t10\t18\t+\tr2\t16\t4\t12\t8\t8\t255\tcg:Z:8M\n"},
+		{runMem, []string{"-l", "6", "-b", "-format", "paf", ref, query},
+			"q\t18\t0\t9\t+\tr1\t16\t4\t13\t9\t9\t255\tcg:Z:9M\n" +
+				"q\t18\t10\t18\t+\tr2\t16\t4\t12\t8\t8\t255\tcg:Z:8M\n" +
+				"q\t18\t1\t7\t-\tr1\t16\t5\t11\t6\t6\t255\tcg:Z:6M\n"},
+	} {
+		if got := runOutput(t, c.run, c.args...); got != c.want {
+			t.Errorf("%v = %q, want %q", c.args, got, c.want)
+		}
+	}
+
+	revIdx := filepath.Join(dir, "rev.esa")
+	if err := runBuild([]string{"-q", "-rev", "-sa", "SaNaive", "-o", revIdx, ref}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runMum([]string{revIdx, query}); err == nil {
+		t.Errorf("mum on an index with the reverse complement did not fail")
+	}
+}
+
+func TestWriteMatches_NoRecord(t *testing.T) {
+	records := []esa.FastaRecord{{Name: "r1", Seq: []byte("ACGT")}, {Name: "r2", Seq: []byte("GG")}}
+	e, m := esa.NewFastaEsa(records, false, "")
+	ref := &index{e, m}
+	query := &esa.FastaRecord{Name: "q", Seq: []byte("ACGT")}
+	matches := []match{{0, -1, 0, 4}, {0, 0, 0, 4}}
+	for _, format := range []string{"mummer", "paf"} {
+		var buf bytes.Buffer
+		if err := writeMatches(&buf, format, ref, query, false, matches); err != nil {
+			t.Fatal(err)
+		}
+		if n := strings.Count(buf.String(), "r1"); n != 1 {
+			t.Errorf("%s output %q has %d matches, want 1", format, buf.String(), n)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	esa "github.com/dadidange/esaMatcher"
+)
+
+// A pattern is a named query sequence.
+type pattern struct {
+	name string
+	seq  []byte
+}
+
+// A position is a match position in record coordinates. Positions on the reverse strand
+// are given by the start of the match on the forward strand.
+type position struct {
+	Record string `json:"record"`
+	Offset int    `json:"offset"`
+	Strand string `json:"strand"`
+}
+
+// A queryResult is the output of esa query for a single pattern.
+type queryResult struct {
+	Name      string     `json:"name"`
+	Length    int        `json:"length"`
+	Match     int        `json:"match"`
+	Start     int        `json:"start"`
+	End       int        `json:"end"`
+	Count     int        `json:"count"`
+	Positions []position `json:"positions"`
+}
+
+// Match patterns against an index.
+func runQuery(args []string) error {
+	fs := newFlagSet("query", "<index> [pattern]...")
+	file := fs.String("f", "", "read the patterns from `file`, \"-\" for the standard input")
+	format := fs.String("format", "auto", "format of the pattern file: lines, fasta, fastq or auto")
+	exact := fs.Bool("exact", false, "only report patterns that match completely instead of their longest prefix")
+	asJSON := fs.Bool("json", false, "write JSON lines instead of TSV")
+	maxPos := fs.Int("max", 100, "report at most `n` positions per pattern, 0 for all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no index given")
+	}
+	if (*file == "") == (fs.NArg() == 1) {
+		return errors.New("give the patterns either as arguments or with -f")
+	}
+	idx, err := readIndex(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	next := argPatterns(fs.Args()[1:])
+	if *file != "" {
+		f, err := openInput(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if next, err = filePatterns(f, *format); err != nil {
+			return err
+		}
+	}
+
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+	if !*asJSON {
+		fmt.Fprintln(w, "#name\tlength\tmatch\tstart\tend\tcount\tpositions")
+	}
+	enc := json.NewEncoder(w)
+	for {
+		p, err := next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		res := query(idx, p, *exact, *maxPos)
+		if *asJSON {
+			err = enc.Encode(res)
+		} else {
+			err = writeQueryTSV(w, res)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Match a single pattern and locate at most maxPos of its positions.
+func query(idx *index, p pattern, exact bool, maxPos int) queryResult {
+	res := queryResult{Name: p.name, Length: len(p.seq), Start: -1, End: -1, Positions: []position{}}
+	m := idx.esa.GetMatch(p.seq)
+	if m.Start() == -1 || exact && m.L() < len(p.seq) {
+		return res
+	}
+	res.Match, res.Start, res.End = m.L(), m.Start(), m.End()
+	res.Count = m.End() - m.Start() + 1
+	sa := idx.esa.Sa()[m.Start() : m.End()+1]
+	if maxPos > 0 && len(sa) > maxPos {
+		sa = sa[:maxPos]
+	}
+	pos := append([]int{}, sa...)
+	sort.Ints(pos)
+	for _, q := range pos {
+		res.Positions = append(res.Positions, locate(idx.records, q, m.L()))
+	}
+	return res
+}
+
+// Translate a match of the given length at position pos of the ESA to record coordinates.
+func locate(m *esa.RecordMap, pos, length int) position {
+	r, off, rev := m.LocateMatch(pos, length)
+	if r == -1 {
+		return position{"-", pos, "+"}
+	}
+	strand := "+"
+	if rev {
+		strand = "-"
+	}
+	return position{m.Names()[r], off, strand}
+}
+
+func writeQueryTSV(w io.Writer, res queryResult) error {
+	var pos []byte
+	for i, p := range res.Positions {
+		if i > 0 {
+			pos = append(pos, ',')
+		}
+		pos = append(pos, p.Record...)
+		pos = append(pos, ':')
+		pos = strconv.AppendInt(pos, int64(p.Offset), 10)
+		pos = append(pos, ':')
+		pos = append(pos, p.Strand...)
+	}
+	if len(pos) == 0 {
+		pos = []byte{'-'}
+	}
+	_, err := fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%s\n",
+		res.Name, res.Length, res.Match, res.Start, res.End, res.Count, pos)
+	return err
+}
+
+// Return the patterns given as arguments, named by their number.
+func argPatterns(args []string) func() (pattern, error) {
+	i := 0
+	return func() (pattern, error) {
+		if i == len(args) {
+			return pattern{}, io.EOF
+		}
+		i++
+		return pattern{strconv.Itoa(i), []byte(args[i-1])}, nil
+	}
+}
+
+// Return the patterns of r in the given format. Lines are named by their number.
+// The format auto detects FASTA and FASTQ by their first byte.
+func filePatterns(r io.Reader, format string) (func() (pattern, error), error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		br = bufio.NewReader(gz)
+	}
+	if format == "auto" {
+		format = "lines"
+		if first, _ := br.Peek(1); len(first) == 1 && first[0] == '>' {
+			format = "fasta"
+		} else if len(first) == 1 && first[0] == '@' {
+			format = "fastq"
+		}
+	}
+	switch format {
+	case "fasta":
+		fr, err := esa.NewFastaReader(br, esa.FastaOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return func() (pattern, error) {
+			rec, err := fr.Read()
+			return pattern{rec.Name, rec.Seq}, err
+		}, nil
+	case "fastq":
+		fr, err := esa.NewFastqReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return func() (pattern, error) {
+			rec, err := fr.Read()
+			return pattern{rec.Name, rec.Seq}, err
+		}, nil
+	case "lines":
+		n := 0
+		return func() (pattern, error) {
+			for {
+				l, err := br.ReadBytes('\n')
+				if len(l) == 0 && err != nil {
+					return pattern{}, err
+				}
+				n++
+				if l = bytes.TrimRight(l, "\r\n"); len(l) > 0 {
+					return pattern{strconv.Itoa(n), l}, nil
+				}
+			}
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown pattern format %q", format)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Run a command and return its output.
+func runOutput(t *testing.T, run func([]string) error, args ...string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := stdout
+	stdout = &buf
+	defer func() { stdout = prev }()
+	if err := run(args); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// Build an index of a small FASTA file in dir and return its path.
+func buildIndex(t *testing.T, dir string) string {
+	t.Helper()
+	fasta := writeFasta(t, dir, "ref.fasta", ">one\nACGTACGT\nAC\n>two\nGGTT\n")
+	path := filepath.Join(dir, "ref.esa")
+	if err := runBuild([]string{"-q", "-rev", "-sa", "SaNaive", "-o", path, fasta}); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestQuery(t *testing.T) {
+	dir := t.TempDir()
+	idx := buildIndex(t, dir)
+
+	out := runOutput(t, runQuery, idx, "CGTA", "GGTA", "ACGT")
+	want := []string{
+		"#name length match start end count positions",
+		"1 4 4 3 one:1:+,one:5:+,one:3:-",
+		"2 4 3 1 two:0:+",
+		"3 4 4 4 one:0:+,one:4:+,one:4:-,one:0:-",
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("query output = %q", out)
+	}
+	for i, l := range lines {
+		f := strings.Split(l, "\t")
+		// The interval bounds depend on the suffix array and are left out.
+		if i > 0 {
+			f = append(f[:3], f[5:]...)
+		}
+		if got := strings.Join(f, " "); got != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got, want[i])
+		}
+	}
+
+	patterns := writeFasta(t, dir, "patterns.fa", ">p\nCGTA\n>q\nGGTA\n")
+	out = runOutput(t, runQuery, "-json", "-exact", "-f", patterns, idx)
+	var results []queryResult
+	dec := json.NewDecoder(strings.NewReader(out))
+	for dec.More() {
+		var r queryResult
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, r)
+	}
+	got := fmt.Sprint(results)
+	if w := "[{p 4 4 %d %d 3 [{one 1 +} {one 5 +} {one 3 -}]} {q 4 0 -1 -1 0 []}]"; got != fmt.Sprintf(w, results[0].Start, results[0].End) {
+		t.Errorf("query -json -exact = %s", got)
+	}
+
+	lines = strings.Split(runOutput(t, runQuery, "-f", writeFasta(t, dir, "p.txt", "ACGT\n\nGGTT\n"), "-max", "1", idx), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "3\t4\t4\t") || strings.Count(lines[1], ":") != 2 {
+		t.Errorf("query of lines = %q", lines)
+	}
+}
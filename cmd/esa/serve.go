@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	esa "github.com/dadidange/esaMatcher"
+)
+
+// Serve queries against an index over HTTP until interrupted.
+func runServe(args []string) error {
+	fs := newFlagSet("serve", "<index>")
+	addr := fs.String("addr", "localhost:8080", "listen on `address`")
+	links := fs.Bool("links", false, "build suffix links for fast MEM queries, about 3n additional integers")
+	var opts esa.HandlerOptions
+	fs.Int64Var(&opts.MaxBodyBytes, "max-body", 1<<20, "maximum request body in `bytes`")
+	fs.IntVar(&opts.MaxQueries, "max-queries", 1000, "maximum number of queries per request")
+	fs.IntVar(&opts.MaxPositions, "max-positions", 1000, "maximum number of positions per query")
+	fs.IntVar(&opts.MaxMEMs, "max-mems", 1000, "maximum number of MEMs per query")
+	fs.IntVar(&opts.MaxConcurrent, "max-concurrent", 64, "maximum number of requests processed at once")
+	quiet := fs.Bool("q", false, "do not report time and memory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("exactly one index is required")
+	}
+	sw := newStopwatch(*quiet)
+	idx, err := readIndex(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	sw.step("read %s", fs.Arg(0))
+	if *links {
+		idx.esa = idx.esa.WithSuffixLinks()
+		sw.step("built suffix links")
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           esa.NewHandler(&idx.esa, idx.records, opts),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	if !*quiet {
+		fmt.Fprintf(os.Stderr, "serving %s on http://%s\n", fs.Arg(0), *addr)
+	}
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdown)
+}
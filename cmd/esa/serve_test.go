@@ -0,0 +1,16 @@
+package main
+
+import "testing"
+
+func TestServe(t *testing.T) {
+	idx := buildIndex(t, t.TempDir())
+	for _, args := range [][]string{
+		{},
+		{"-q", idx + ".missing"},
+		{"-q", "-addr", "localhost:-1", idx},
+	} {
+		if err := runServe(args); err == nil {
+			t.Errorf("serve %v did not fail", args)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package esaMatcher
+
+import "bytes"
+
+// Number of characters that CompressedEsa.GetMatch extracts from the text at once.
+const csaWindow = 64
+
+// A CompressedEsa answers the queries of the ESA with a fraction of its memory.
+// It keeps the FMIndex of the text with its sampled suffix array, the rows of every
+// SampleRate-th text position, the permuted lcp array PLCP in 2n bits and the child table
+// as balanced parentheses in 2n bits. The text, the suffix array and the lcp array are not
+// stored but computed on demand, see Extract, Sa and Lcp.
+//
+// Each access of the text and the suffix array takes at most SampleRate-1 steps of the LF mapping,
+// hence the sample rate trades the size of the index, about (2.25 + σ/16)n + n⌈log₂ n⌉/(4·SampleRate)
+// bytes for a text of length n over σ distinct symbols, against the time of its queries.
+//
+// Implementation
+//
+// The lcp values of consecutive text positions decrease by one at most, PLCP[p+1] ≥ PLCP[p]-1.
+// Hence PLCP[p]+2p strictly increases with p and is less than 2n. PLCP is stored as a bit vector
+// of length 2n, in which the bit PLCP[p]+2p is set for every text position p,
+// such that PLCP[p] = select(p)-2p (Sadakane, 2002).
+//
+// The l-indices of an lcp-interval are the positions of the minimum of the lcp array within it.
+// The parentheses of the lcp array, see lcpParentheses, find the leftmost minimum of any range
+// without access to the lcp values. They replace the child table of the ESA.
+type CompressedEsa struct {
+	fm         *FMIndex
+	strandSize int
+	// Rows of the text positions 0, SampleRate, 2·SampleRate, ... and of the final sentinel.
+	isa     *intVector
+	lastRow int
+	plcp    *bitVector
+	cld     *parentheses
+	// Whether the text holds RecordSeparators, see NewFastaEsa.
+	separators bool
+}
+
+// NewCompressedEsa builds the CompressedEsa of s with the given suffix array library.
+// If rev is true, the reverse complement is included as by NewRevEsa.
+// The lcp array is computed from the suffix array with the Φ algorithm of Kärkkäinen et al. (2009),
+// the ESA itself is never built. Every sampleRate-th text position of the suffix array is kept.
+//
+// The construction keeps the text and its suffix array, about 9n bytes for a text of length n,
+// and the child table needs a stack of up to n+1 integers, see lcpParentheses.
+// Hence the memory peaks at about 17n bytes in addition to the index, compared to at least 24n bytes for the ESA.
+func NewCompressedEsa(s []byte, rev bool, saLib string, sampleRate int) *CompressedEsa {
+	t, sa := strandText(s, rev, saLib)
+	c := newCompressedEsa(newFMIndex(t, sa, len(s), sampleRate), sa, len(s), false)
+	n, rate := len(t), c.fm.sampleRate
+	// Φ(p) is the position of the suffix that precedes the suffix at p in the suffix array.
+	// The rows of p are taken from the samples block by block instead of an inverse suffix array.
+	rows := make([]int, rate)
+	c.plcp = newBitVector(2*n + 1)
+	l := 0
+	for a := 0; a < n; a += rate {
+		b := minInt(a+rate, n)
+		x, r := n-1, c.lastRow
+		if b < n {
+			x, r = b, c.isa.get(b/rate)
+		} else {
+			rows[x-a] = r
+		}
+		for x > a {
+			r = c.fm.lf(r)
+			x--
+			rows[x-a] = r
+		}
+		for p := a; p < b; p++ {
+			if i := rows[p-a]; i == 0 {
+				l = 0
+			} else {
+				for q := sa[i-1]; p+l < n && q+l < n && !c.isSentinel(p+l) && !c.isSentinel(q+l) && t[p+l] == t[q+l]; {
+					l++
+				}
+			}
+			c.plcp.set(l + 2*p)
+			if l > 0 {
+				l--
+			}
+		}
+	}
+	c.plcp.build()
+	c.cld = lcpParentheses(n, func(i int) int { return c.lcpAt(sa[i]) })
+	return c
+}
+
+// Compress returns the CompressedEsa of the text of the ESA, see NewCompressedEsa.
+// As in the ESA, a query is only matched up to its first RecordSeparator if the ESA
+// was built by NewFastaEsa. Besides the ESA, the construction needs a stack of up to
+// n+1 integers, see lcpParentheses.
+func (e *Esa) Compress(sampleRate int) *CompressedEsa {
+	n := len(e.s)
+	c := newCompressedEsa(e.FMIndex(sampleRate), e.sa, e.strandSize, e.separators != nil)
+	c.plcp = newBitVector(2*n + 1)
+	for i, p := range e.sa {
+		// The first row has no predecessor, its lcp of -1 is stored as 0.
+		l := e.lcp[i]
+		if l < 0 {
+			l = 0
+		}
+		c.plcp.set(l + 2*p)
+	}
+	c.plcp.build()
+	c.cld = lcpParentheses(n, func(i int) int { return e.lcp[i] })
+	return c
+}
+
+// Initialise the CompressedEsa with the FMIndex and sample the rows of the text positions.
+func newCompressedEsa(fm *FMIndex, sa []int, strandSize int, separators bool) *CompressedEsa {
+	c := &CompressedEsa{fm: fm, strandSize: strandSize, separators: separators}
+	n, rate := len(sa), fm.sampleRate
+	c.isa = newIntVector((n-1)/rate+1, n-1)
+	for i, p := range sa {
+		if p%rate == 0 {
+			c.isa.set(p/rate, i)
+		}
+		if p == n-1 {
+			c.lastRow = i
+		}
+	}
+	return c
+}
+
+// Return the length of the indexed text including the sentinels.
+func (c *CompressedEsa) Len() int { return c.fm.Len() }
+
+// Return the distance between the sampled text positions.
+func (c *CompressedEsa) SampleRate() int { return c.fm.sampleRate }
+
+// Return the single strand size, see Esa.StrandSize.
+func (c *CompressedEsa) StrandSize() int { return c.strandSize }
+
+// Return the FMIndex of the text.
+func (c *CompressedEsa) FMIndex() *FMIndex { return c.fm }
+
+// Size returns the approximate size of the index in bytes.
+func (c *CompressedEsa) Size() int {
+	return c.fm.size() + c.isa.size() + c.plcp.size() + c.cld.size()
+}
+
+// Sa returns the entry of the suffix array at row i in at most SampleRate-1 steps of the LF mapping.
+func (c *CompressedEsa) Sa(i int) int { return c.fm.position(i) }
+
+// Lcp returns the entry of the lcp array at row i, see Esa.Lcp, which is -1 for the rows 0 and Len().
+func (c *CompressedEsa) Lcp(i int) int {
+	if i == 0 || i == c.Len() {
+		return -1
+	}
+	return c.lcpAt(c.Sa(i))
+}
+
+// Return the lcp of the suffix at text position p and its predecessor in the suffix array.
+func (c *CompressedEsa) lcpAt(p int) int { return c.plcp.select1(p) - 2*p }
+
+// Return the row of the suffix at text position p in at most SampleRate-1 steps of the LF mapping.
+func (c *CompressedEsa) rowOf(p int) int {
+	rate := c.fm.sampleRate
+	x := (p + rate - 1) / rate * rate
+	if x >= c.Len() {
+		x = c.Len() - 1
+	}
+	r := c.lastRow
+	if x%rate == 0 {
+		r = c.isa.get(x / rate)
+	}
+	for ; x > p; x-- {
+		r = c.fm.lf(r)
+	}
+	return r
+}
+
+// Extract returns the text from position start up to end, not included, in
+// end-start+SampleRate-1 steps of the LF mapping at most. The sentinels appear as
+// in FMIndex.Text.
+func (c *CompressedEsa) Extract(start, end int) []byte {
+	t := make([]byte, end-start)
+	if start >= end {
+		return t
+	}
+	var r int
+	if end == c.Len() {
+		// The suffix at position 0 is preceded by the final sentinel.
+		t[len(t)-1] = c.fm.bwt[c.fm.endRow]
+		end, r = end-1, c.lastRow
+	} else {
+		r = c.rowOf(end)
+	}
+	for ; end > start; end-- {
+		t[end-1-start] = c.fm.bwt[r]
+		r = c.fm.lf(r)
+	}
+	return t
+}
+
+// Report whether the text holds a sentinel at position p.
+func (c *CompressedEsa) isSentinel(p int) bool {
+	return p == c.Len()-1 || p == c.strandSize
+}
+
+// Return the position of the first sentinel at or after position p.
+func (c *CompressedEsa) sentinelAfter(p int) int {
+	if p <= c.strandSize {
+		return c.strandSize
+	}
+	return c.Len() - 1
+}
+
+// Interval returns the interval of the index that starts and ends at the given rows, see Esa.Interval.
+func (c *CompressedEsa) Interval(start, end int) EsaInterval {
+	if start >= end {
+		if start >= 0 {
+			return EsaInterval{start, end, start, c.Lcp(end)}
+		}
+		return EmptyEsaInterval()
+	}
+	mid := c.cld.rmq(start+1, end)
+	return EsaInterval{start, end, mid, c.Lcp(mid)}
+}
+
+// Root returns the interval of the index that contains all suffixes.
+func (c *CompressedEsa) Root() EsaInterval { return c.Interval(0, c.Len()-1) }
+
+// GetInterval returns the child interval of i that starts with ch, see Esa.GetInterval.
+func (c *CompressedEsa) GetInterval(i EsaInterval, ch byte) EsaInterval {
+	if c.separators && ch == RecordSeparator {
+		return EmptyEsaInterval()
+	}
+	child, _ := c.getInterval(i, ch)
+	return child
+}
+
+// Find the child interval of i that starts with ch and return it together with the
+// text position of its first suffix.
+//
+// Implementation
+//
+// As in the ESA, the children of i are separated by its l-indices. The first one is the middle
+// of i, each further l-index is the leftmost minimum of the lcp array behind the previous one
+// as long as its lcp equals l. The suffix array is accessed once for every child and l-index.
+func (c *CompressedEsa) getInterval(i EsaInterval, ch byte) (EsaInterval, int) {
+	isChar := func(p int) bool {
+		return !c.isSentinel(p) && c.fm.bwt[c.rowOf(p+1)] == ch
+	}
+	if i.start == i.end {
+		if p := c.Sa(i.start); isChar(p) {
+			return i, p
+		}
+		return EmptyEsaInterval(), -1
+	}
+	l := i.l
+	lower, p := i.start, c.Sa(i.start)
+	upper, q := i.mid, c.Sa(i.mid)
+	for {
+		if isChar(p + l) {
+			return c.Interval(lower, upper-1), p
+		}
+		lower, p = upper, q
+		if lower == i.end {
+			break
+		}
+		upper = c.cld.rmq(lower+1, i.end)
+		if q = c.Sa(upper); c.lcpAt(q) != l {
+			break
+		}
+	}
+	if isChar(p + l) {
+		return c.Interval(lower, i.end), p
+	}
+	return EmptyEsaInterval(), -1
+}
+
+// GetMatch returns the longest prefix of the query that matches the index, see Esa.GetMatch.
+// The returned interval equals the one of the ESA.
+//
+// Implementation
+//
+// GetMatch walks down the lcp-interval tree with GetInterval as the ESA does.
+// The characters between two branchings are compared with the text extracted in windows
+// of 64 characters.
+func (c *CompressedEsa) GetMatch(query []byte) EsaInterval {
+	if c.separators {
+		if k := bytes.IndexByte(query, RecordSeparator); k >= 0 {
+			query = query[:k]
+		}
+	}
+	m := len(query)
+	in := c.Root()
+	k, p := 0, 0
+	// Extend the match and skip common prefixes, report if a mismatch was found.
+	skip := func() bool {
+		l := in.l
+		if in.start == in.end || l > m {
+			l = m
+		}
+		stop := c.sentinelAfter(p) - p
+		for k < l {
+			if k >= stop {
+				in.l = k
+				return true
+			}
+			end := minInt(minInt(l, k+csaWindow), stop)
+			for j, b := range c.Extract(p+k, p+end) {
+				if b != query[k+j] {
+					in.l = k + j
+					return true
+				}
+			}
+			k = end
+		}
+		return false
+	}
+	for k < m {
+		child, q := c.getInterval(in, query[k])
+		if child.start == -1 && child.end == -1 {
+			if k == 0 {
+				return child
+			}
+			in.l = k
+			return in
+		}
+		k++
+		in, p = child, q
+		if skip() {
+			return in
+		}
+	}
+	in.l = m
+	return in
+}
+
+// Locate returns the positions of all occurrences of p in ascending order, see FMIndex.Locate.
+func (c *CompressedEsa) Locate(p []byte) []int { return c.fm.Locate(p) }
+
+// Count returns the number of occurrences of p, see FMIndex.Count.
+func (c *CompressedEsa) Count(p []byte) int { return c.fm.Count(p) }
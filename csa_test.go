@@ -0,0 +1,175 @@
+package esaMatcher
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestCompressedEsa(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		[]byte("A$C#AC$"),
+		{},
+		ranseq(3000, "ACGTN"),
+	}
+	queries := [][]byte{[]byte("A"), []byte("AC"), []byte("$"), []byte("C#"), []byte("GGG"), []byte("X"), {}}
+	for i := 0; i < 50; i++ {
+		queries = append(queries, ranseq(1+i%12, "ACGT"))
+	}
+	for _, s := range seqs {
+		for _, rev := range []bool{false, true} {
+			e := NewEsa(s, "")
+			if rev {
+				e = NewRevEsa(s, "")
+			}
+			for _, rate := range []int{1, 4, 32} {
+				for _, c := range []*CompressedEsa{NewCompressedEsa(s, rev, "", rate), e.Compress(rate)} {
+					for i := 0; i <= len(e.Sa()); i++ {
+						if i < len(e.Sa()) && c.Sa(i) != e.Sa()[i] {
+							t.Fatalf("Sa(%d) = %d, want %d", i, c.Sa(i), e.Sa()[i])
+						}
+						if c.Lcp(i) != e.Lcp()[i] {
+							t.Fatalf("Lcp(%d) = %d, want %d", i, c.Lcp(i), e.Lcp()[i])
+						}
+					}
+					if c.Root() != e.Root() {
+						t.Fatalf("Root() = %v, want %v", c.Root(), e.Root())
+					}
+					for i := 0; i < len(e.Sa()); i += 1 + i/3 {
+						for j := i; j <= len(e.Sa()); j += 1 + j/2 {
+							if got, want := c.Extract(i, j), e.Sequence()[i:j]; !bytes.Equal(got, want) {
+								t.Fatalf("Extract(%d, %d) = %q, want %q", i, j, got, want)
+							}
+						}
+					}
+					for _, q := range queries {
+						want, got := e.GetMatch(q), c.GetMatch(q)
+						if got != want {
+							t.Errorf("GetMatch(%s) = %v, want %v in %.20s", q, got, want, s)
+						}
+						for ie, ic, k := e.Root(), c.Root(), 0; k < len(q) && ie.Start() != -1; k++ {
+							ie, ic = e.GetInterval(ie, q[k]), c.GetInterval(ic, q[k])
+							if ic != ie {
+								t.Fatalf("GetInterval(%s, %c) = %v, want %v in %.20s", q[:k], q[k], ic, ie, s)
+							}
+						}
+						if fmt.Sprint(c.Locate(q)) != fmt.Sprint(c.FMIndex().Locate(q)) {
+							t.Errorf("Locate(%s) = %v", q, c.Locate(q))
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestBitVector(t *testing.T) {
+	b := newBitVector(1000)
+	var ones []int
+	for i := 0; i < 1000; i += 1 + i%7 {
+		b.set(i)
+		ones = append(ones, i)
+	}
+	b.build()
+	for k, i := range ones {
+		if b.rank(i) != k || !b.get(i) {
+			t.Errorf("rank(%d) = %d, want %d", i, b.rank(i), k)
+		}
+		if b.select1(k) != i {
+			t.Errorf("select1(%d) = %d, want %d", k, b.select1(k), i)
+		}
+	}
+}
+
+func TestBitVector_Large(t *testing.T) {
+	// Three superblocks, the directory is shifted as if 2^33 set bits preceded them.
+	n := 3 * bitSuperWords * 64
+	b := newBitVector(n)
+	var ones []int
+	for i := 0; i < n; i += 1 + i%1000 {
+		b.set(i)
+		ones = append(ones, i)
+	}
+	b.build()
+	const offset = 1 << 33
+	for k := range b.supers {
+		b.supers[k] += offset
+	}
+	for k := 0; k < len(ones); k += 97 {
+		i := ones[k]
+		if b.rank(i) != offset+k {
+			t.Errorf("rank(%d) = %d, want %d", i, b.rank(i), offset+k)
+		}
+		if b.select1(offset+k) != i {
+			t.Errorf("select1(%d) = %d, want %d", offset+k, b.select1(offset+k), i)
+		}
+	}
+	if b.rank(n) != offset+len(ones) {
+		t.Errorf("rank(%d) = %d, want %d", n, b.rank(n), offset+len(ones))
+	}
+}
+
+func TestCompressedEsa_Fasta(t *testing.T) {
+	records := []FastaRecord{{"a", []byte("ACGTAC")}, {"b", []byte("GGGACGT")}, {"c", ranseq(200, "ACGT")}}
+	e, _ := NewFastaEsa(records, true, "")
+	c := e.Compress(8)
+	for _, q := range [][]byte{[]byte("ACGG"), []byte("AC%GGG"), []byte("TAC"), records[2].Seq[50:90], []byte("%")} {
+		if got, want := c.GetMatch(q), e.GetMatch(q); got != want {
+			t.Errorf("GetMatch(%s) = %v, want %v", q, got, want)
+		}
+	}
+	// Whole patterns are only found within records.
+	for _, q := range [][]byte{[]byte("C%G"), []byte("%"), []byte("GGGACGT"), records[2].Seq[50:90]} {
+		var want []int
+		if m := e.GetMatch(q); m.L() == len(q) {
+			want = append(want, e.Sa()[m.Start():m.End()+1]...)
+			sort.Ints(want)
+		}
+		if got := c.Locate(q); fmt.Sprint(got) != fmt.Sprint(want) || c.Count(q) != len(want) {
+			t.Errorf("Locate(%s) = %v, Count = %d, want %v", q, got, c.Count(q), want)
+		}
+	}
+}
+
+func TestIntVector(t *testing.T) {
+	for _, max := range []int{0, 1, 5, 1000, 1<<40 + 3} {
+		v := newIntVector(100, max)
+		for i := 0; i < 100; i++ {
+			v.set(i, (i*7919)%(max+1))
+		}
+		for i := 0; i < 100; i++ {
+			if got := v.get(i); got != (i*7919)%(max+1) {
+				t.Fatalf("get(%d) = %d, want %d with max %d", i, got, (i*7919)%(max+1), max)
+			}
+		}
+	}
+}
+
+func TestParentheses(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{2, 3, 10, 300, 2000} {
+		lcp := make([]int, n+1)
+		for i := range lcp {
+			lcp[i] = r.Intn(4 + n%7)
+		}
+		p := lcpParentheses(n, func(i int) int { return lcp[i] })
+		lcp[0], lcp[n] = -1, -1
+		for k := 0; k < 500; k++ {
+			a := 1 + r.Intn(n)
+			b := a + r.Intn(n+1-a)
+			want := a
+			for i := a; i <= b; i++ {
+				if lcp[i] < lcp[want] {
+					want = i
+				}
+			}
+			if got := p.rmq(a, b); got != want {
+				t.Fatalf("rmq(%d, %d) = %d, want %d in %v", a, b, got, want, lcp)
+			}
+		}
+	}
+}
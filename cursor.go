@@ -0,0 +1,313 @@
+package esaMatcher
+
+import "sort"
+
+// The navigation structures that allow to move between lcp-intervals in constant
+// or logarithmic time: the inverse suffix array, a segment tree over the lcp array and,
+// with WithSuffixLinks, the suffix link of every lcp-interval.
+type navigation struct {
+	isa []int
+	lcp *minTree
+	// The bounds of the suffix link of the lcp-interval with the first l-index k
+	// are stored at 2k and 2k+1.
+	links []int
+}
+
+// WithSuffixLinks returns a copy of the ESA that supports suffix links, that is moving
+// from the interval of a string cw to the interval of w, see Cursor.SuffixLink.
+//
+// The suffix link of every lcp-interval is computed in O(n log n) time and stored,
+// together with the inverse suffix array and a range minimum structure over the lcp array.
+// These take about 5n integers in addition to the ESA. The returned ESA shares all other arrays with e.
+//
+// Concept
+//
+// Every lcp-interval l-[i..j] is identified by its first l-index, the smallest k in [i+1..j] with
+// lcp[k] = l. The suffixes SA[k]+1 for k in [i..j] start with the same string w of length l-1,
+// which is right branching as well, hence w is the label of an (l-1)-interval.
+// It is the largest interval around ISA[SA[i]+1] where all lcp values are at least l-1.
+// Its bounds are the nearest smaller values of l-1 to the left and right in the lcp array.
+func (e *Esa) WithSuffixLinks() Esa {
+	c := e.withNavigation()
+	if c.nav.links != nil {
+		return c
+	}
+	nav := *c.nav
+	n := len(e.sa)
+	nav.links = make([]int, 2*(n+1))
+	for k := 1; k < n; k++ {
+		l := e.lcp[k]
+		if l <= 0 {
+			continue
+		}
+		i := nav.lcp.prevLess(k, l)
+		if nav.lcp.prevLess(k-1, l+1) != i {
+			// k is not the first l-index of its interval.
+			continue
+		}
+		r := nav.isa[e.sa[i]+1]
+		nav.links[2*k] = nav.lcp.prevLess(r, l-1)
+		nav.links[2*k+1] = nav.lcp.nextLess(r+1, l-1) - 1
+	}
+	c.nav = &nav
+	return c
+}
+
+// Return a copy of the ESA with the navigation structures.
+func (e *Esa) withNavigation() Esa {
+	c := *e
+	if c.nav == nil {
+		c.nav = &navigation{isa: Isa(e.sa), lcp: newMinTree(e.lcp)}
+	}
+	return c
+}
+
+// Return the suffix link of the lcp-interval iv, whose lcp value is greater than 0.
+func (e *Esa) suffixLink(iv EsaInterval) EsaInterval {
+	return e.Interval(e.nav.links[2*iv.mid], e.nav.links[2*iv.mid+1])
+}
+
+// A Cursor points to the interval of a string w in the ESA, that is the interval
+// of all suffixes starting with w. It can be moved down the tree by appending characters
+// to w and, by following suffix links, up and sideways by removing the first character of w.
+//
+// With suffix links, see WithSuffixLinks, a query can be streamed through the cursor
+// in linear time. Without them, each suffix link restarts at the root.
+type Cursor struct {
+	e     *Esa
+	iv    EsaInterval
+	depth int
+	// The deepest lcp-interval whose label is a prefix of w.
+	node EsaInterval
+}
+
+// NewCursor returns a Cursor that points to the root of e, that is the empty string.
+func (e *Esa) NewCursor() *Cursor {
+	return &Cursor{e, e.Root(), 0, e.Root()}
+}
+
+// Return the length of the string the cursor points to.
+func (c *Cursor) Depth() int { return c.depth }
+
+// Return the interval the cursor points to.
+// Its length is set to the depth of the cursor.
+func (c *Cursor) Interval() EsaInterval {
+	iv := c.iv
+	iv.l = c.depth
+	return iv
+}
+
+// Report whether the cursor points to the lcp-interval iv itself rather than into the edge above it.
+func (c *Cursor) atNode() bool {
+	return c.iv.start != c.iv.end && c.depth >= c.iv.l
+}
+
+// Extend appends ch to the string w the cursor points to and reports whether wch occurs
+// in the ESA. If it does not, the cursor is not moved.
+func (c *Cursor) Extend(ch byte) bool {
+	iv := c.e.extend(c.iv, c.depth, ch)
+	if iv.start == -1 {
+		return false
+	}
+	if c.atNode() {
+		c.node = c.iv
+	}
+	c.iv = iv
+	c.depth++
+	return true
+}
+
+// SuffixLink removes the first character of the string the cursor points to.
+// At the root, it does nothing.
+//
+// Concept
+//
+// Let the cursor point to cw of length d and let u be the deepest lcp-interval on the path to cw,
+// whose label cv has length l <= d. The suffix link of u leads to the interval of v.
+// From there, the remaining d-l characters of w are rescanned: since w occurs in the ESA,
+// only the first character of each edge is compared and whole edges are skipped.
+//
+// When a query is streamed through the cursor, the number of lcp-intervals on the path of the cursor
+// decreases by at most one per suffix link and increases with every rescanned edge. Hence
+// all suffix links take O(mσ) time in total for a query of length m over σ symbols.
+func (c *Cursor) SuffixLink() {
+	e := c.e
+	switch {
+	case c.depth == 0:
+		return
+	case c.depth == 1:
+		c.iv, c.depth, c.node = e.Root(), 0, e.Root()
+		return
+	case e.nav == nil || e.nav.links == nil:
+		// Without links, search w again from the root.
+		p := e.sa[c.iv.start] + 1
+		w := e.s[p : p+c.depth-1]
+		c.iv, c.depth, c.node = e.Root(), 0, e.Root()
+		for _, ch := range w {
+			c.Extend(ch)
+		}
+		return
+	}
+	// Text position of w, the string to move to.
+	p := e.sa[c.iv.start] + 1
+	d := c.depth - 1
+	u := c.node
+	if c.atNode() {
+		u = c.iv
+	}
+	if u.l > 0 {
+		u = e.suffixLink(u)
+	}
+	// Rescan w from u, whose label is a prefix of w.
+	c.node = u
+	c.iv, c.depth = u, u.l
+	if u.l < 0 {
+		c.depth = 0
+	}
+	for c.depth < d {
+		child := e.GetInterval(c.iv, e.s[p+c.depth])
+		if child.start == child.end || child.l >= d {
+			c.node = c.iv
+			c.iv, c.depth = child, d
+			break
+		}
+		c.node = c.iv
+		c.iv, c.depth = child, child.l
+	}
+	if c.atNode() {
+		c.node = c.iv
+	}
+}
+
+// MatchingStatistics returns for every position i of the query the length of the longest
+// prefix of query[i:] that occurs in the ESA.
+// With suffix links, see WithSuffixLinks, this takes O(mσ) time for a query of length m
+// over σ symbols.
+func (e *Esa) MatchingStatistics(query []byte) []int {
+	ms := make([]int, len(query))
+	c := e.NewCursor()
+	for i := range query {
+		for i+c.depth < len(query) && c.Extend(query[i+c.depth]) {
+		}
+		ms[i] = c.depth
+		c.SuffixLink()
+	}
+	return ms
+}
+
+// A MEM is a maximal exact match between a query and the ESA, that is a match
+// that can neither be extended to the left nor to the right.
+type MEM struct {
+	// Start of the match in the query.
+	QueryPos int
+	// Start of the match in the sequence of the ESA.
+	RefPos int
+	// Length of the match.
+	Length int
+}
+
+// FindMEMs returns all maximal exact matches of at least minLength between the query and
+// the ESA, sorted by their position in the query and in the ESA.
+// With suffix links, see WithSuffixLinks, the matching statistics of the query
+// are computed in O(mσ) time, the enclosing intervals of each match are found in O(log n) time each.
+//
+// Implementation
+//
+// For every position i of the query, the cursor points to the interval of its matching statistic.
+// All suffixes of this interval are right maximal matches. The suffixes of the enclosing
+// lcp-intervals that are not part of the child interval on the path of the query match
+// exactly as many characters as the depth of the enclosing interval, so they are right maximal as well.
+// Of those, all matches that can not be extended to the left are reported.
+func (e *Esa) FindMEMs(query []byte, minLength int) []MEM {
+	return e.FindMEMsN(query, minLength, -1)
+}
+
+// FindMEMsN is like FindMEMs but returns at most n MEMs, or all if n < 0.
+// The query is searched from left to right until n MEMs are found, hence the
+// MEMs of the leftmost query positions are returned.
+func (e *Esa) FindMEMsN(query []byte, minLength, n int) []MEM {
+	if minLength < 1 {
+		minLength = 1
+	}
+	var mems []MEM
+	full := func() bool { return n >= 0 && len(mems) >= n }
+	c := e.NewCursor()
+	report := func(i, from, to, length int) {
+		for k := from; k <= to && !full(); k++ {
+			p := e.sa[k]
+			if i == 0 || p == 0 || e.isSentinel(p-1) || e.s[p-1] != query[i-1] {
+				mems = append(mems, MEM{i, p, length})
+			}
+		}
+	}
+	for i := 0; i < len(query) && !full(); i++ {
+		for i+c.depth < len(query) && c.Extend(query[i+c.depth]) {
+		}
+		if c.depth >= minLength {
+			start, end := c.iv.start, c.iv.end
+			report(i, start, end, c.depth)
+			// Walk up the enclosing lcp-intervals.
+			for {
+				parent := e.lcp[start]
+				if e.lcp[end+1] > parent {
+					parent = e.lcp[end+1]
+				}
+				if parent < minLength || full() {
+					break
+				}
+				ps, pe := e.parentBounds(start, end, parent)
+				report(i, ps, start-1, parent)
+				report(i, end+1, pe, parent)
+				start, end = ps, pe
+			}
+		}
+		c.SuffixLink()
+	}
+	sort.Slice(mems, func(a, b int) bool {
+		if mems[a].QueryPos != mems[b].QueryPos {
+			return mems[a].QueryPos < mems[b].QueryPos
+		}
+		return mems[a].RefPos < mems[b].RefPos
+	})
+	return mems
+}
+
+// FindMUMs returns all maximal unique matches of at least minLength between the query and
+// the ESA, that is all MEMs whose string occurs exactly once in the ESA and exactly once
+// in the query. The MUMs are sorted like the MEMs of FindMEMs.
+//
+// The occurrences in the query are counted with an ESA of the query, which is built
+// with the default suffix array library.
+func (e *Esa) FindMUMs(query []byte, minLength int) []MEM {
+	mems := e.FindMEMs(query, minLength)
+	if len(mems) == 0 {
+		return nil
+	}
+	q := NewEsa(query, "")
+	unique := func(f *Esa, w []byte) bool {
+		m := f.GetMatch(w)
+		return m.start != -1 && m.l == len(w) && m.start == m.end
+	}
+	var mums []MEM
+	for _, m := range mems {
+		w := query[m.QueryPos : m.QueryPos+m.Length]
+		if unique(e, w) && unique(&q, w) {
+			mums = append(mums, m)
+		}
+	}
+	return mums
+}
+
+// Return the bounds of the lcp-interval with lcp value l that encloses the interval [start..end].
+func (e *Esa) parentBounds(start, end, l int) (int, int) {
+	if e.nav != nil {
+		return e.nav.lcp.prevLess(start, l), e.nav.lcp.nextLess(end+1, l) - 1
+	}
+	for start > 0 && e.lcp[start] >= l {
+		start--
+	}
+	for e.lcp[end+1] >= l {
+		end++
+	}
+	return start, end
+}
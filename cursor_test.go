@@ -0,0 +1,224 @@
+package esaMatcher
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMinTree(t *testing.T) {
+	a := []int{-1, 3, 0, 5, 2, 2, 7, 1, 4, -1}
+	m := newMinTree(a)
+	for i := range a {
+		for j := i; j < len(a); j++ {
+			want := a[i]
+			for _, v := range a[i : j+1] {
+				if v < want {
+					want = v
+				}
+			}
+			if got := m.min(i, j); got != want {
+				t.Errorf("min(%d,%d) = %d, want %d", i, j, got, want)
+			}
+		}
+		for v := -1; v < 8; v++ {
+			prev, next := -1, len(a)
+			for x := i; x >= 0; x-- {
+				if a[x] < v {
+					prev = x
+					break
+				}
+			}
+			for x := i; x < len(a); x++ {
+				if a[x] < v {
+					next = x
+					break
+				}
+			}
+			if got := m.prevLess(i, v); got != prev {
+				t.Errorf("prevLess(%d,%d) = %d, want %d", i, v, got, prev)
+			}
+			if got := m.nextLess(i, v); got != next {
+				t.Errorf("nextLess(%d,%d) = %d, want %d", i, v, got, next)
+			}
+		}
+	}
+}
+
+func TestMatchingStatistics(t *testing.T) {
+	refs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		ranseq(5000, "ACGT"),
+	}
+	queries := [][]byte{
+		[]byte("ACATATAAGTAAGGTTTTACAAAC"),
+		ranseq(300, "ACGT"),
+		ranseq(300, "ACGTN"),
+	}
+	for _, ref := range refs {
+		for _, e := range []Esa{NewEsa(ref, ""), NewRevEsa(ref, "")} {
+			for _, le := range []Esa{e, e.WithSuffixLinks()} {
+				for _, q := range queries {
+					ms := le.MatchingStatistics(q)
+					for i := range q {
+						m := e.GetMatch(q[i:])
+						want := 0
+						if m.Start() != -1 {
+							want = m.L()
+						}
+						if ms[i] != want {
+							t.Fatalf("ms[%d] = %d, want %d", i, ms[i], want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCursor_SuffixLink(t *testing.T) {
+	refs := [][]byte{
+		[]byte("ACAAACATAT"),       //OhleBusch Book
+		[]byte("AAGTAAGGAAGTAAGG"), //phylonium & andi
+		[]byte("A$C#AC$"),
+		ranseq(3000, "AC"),
+		ranseq(3000, "ACGT"),
+	}
+	for _, ref := range refs {
+		for _, e := range []Esa{NewEsa(ref, ""), NewRevEsa(ref, "")} {
+			le := e.WithSuffixLinks()
+			s := e.Sequence()
+			for p := 0; p < len(s); p += 1 + len(s)/50 {
+				// Move the cursor down the suffix at p and remove its characters one by one.
+				c := le.NewCursor()
+				for !le.isSentinel(p+c.Depth()) && c.Depth() < 40 && c.Extend(s[p+c.Depth()]) {
+				}
+				for q := p; c.Depth() > 0; q++ {
+					c.SuffixLink()
+					want := e.GetMatch(s[q+1 : q+1+c.Depth()])
+					if c.Depth() == 0 {
+						want = e.Root()
+					}
+					if got := c.Interval(); got.start != want.start || got.end != want.end {
+						t.Fatalf("SuffixLink at %d with depth %d points to [%d..%d], want [%d..%d]",
+							q, c.Depth(), got.start, got.end, want.start, want.end)
+					}
+					// The cursor can be extended again after the link.
+					if d := c.Depth(); !le.isSentinel(q+1+d) && c.Extend(s[q+1+d]) {
+						want := e.GetMatch(s[q+1 : q+2+d])
+						if got := c.Interval(); got.start != want.start || got.end != want.end {
+							t.Fatalf("Extend after SuffixLink at %d points to [%d..%d], want [%d..%d]",
+								q, got.start, got.end, want.start, want.end)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestFindMEMs(t *testing.T) {
+	refs := [][]byte{
+		[]byte("ACAAACATAT"),
+		[]byte("AAGTAAGGAAGTAAGG"),
+		ranseq(2000, "ACGT"),
+	}
+	queries := [][]byte{
+		[]byte("ACATATAAGTAAGGTTTTACAAAC"),
+		ranseq(200, "ACGT"),
+	}
+	for _, ref := range refs {
+		for _, e := range []Esa{NewEsa(ref, ""), NewRevEsa(ref, "")} {
+			for _, le := range []Esa{e, e.WithSuffixLinks()} {
+				for _, q := range queries {
+					for _, minLength := range []int{1, 3, 6} {
+						got := fmt.Sprint(le.FindMEMs(q, minLength))
+						want := fmt.Sprint(naiveMEMs(&e, q, minLength))
+						if got != want {
+							t.Fatalf("FindMEMs(%s, %d) = %s, want %s", q, minLength, got, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// Find all maximal exact matches by comparing every pair of positions.
+func naiveMEMs(e *Esa, q []byte, minLength int) []MEM {
+	var mems []MEM
+	s := e.Sequence()
+	for i := range q {
+		for p := range s {
+			if i > 0 && p > 0 && !e.isSentinel(p-1) && s[p-1] == q[i-1] {
+				continue
+			}
+			l := 0
+			for i+l < len(q) && !e.isSentinel(p+l) && s[p+l] == q[i+l] {
+				l++
+			}
+			if l >= minLength {
+				mems = append(mems, MEM{i, p, l})
+			}
+		}
+	}
+	return mems
+}
+
+func TestFindMEMsN(t *testing.T) {
+	e := NewRevEsa(ranseq(2000, "ACGT"), "")
+	e = e.WithSuffixLinks()
+	q := ranseq(200, "ACGT")
+	all := e.FindMEMs(q, 3)
+	for _, n := range []int{0, 1, 10, len(all), len(all) + 1} {
+		got := e.FindMEMsN(q, 3, n)
+		if want := minInt(n, len(all)); len(got) != want {
+			t.Fatalf("FindMEMsN(%d) returned %d MEMs, want %d", n, len(got), want)
+		}
+		// The MEMs of all but the last reported query position are complete.
+		for k, m := range got {
+			if m.QueryPos < got[len(got)-1].QueryPos && m != all[k] {
+				t.Errorf("FindMEMsN(%d) MEM %d = %v, want %v", n, k, m, all[k])
+			}
+		}
+	}
+}
+
+func TestFindMUMs(t *testing.T) {
+	refs := [][]byte{
+		[]byte("ACAAACATAT"),
+		[]byte("AAGTAAGGAAGTAAGG"),
+		ranseq(2000, "ACGT"),
+	}
+	queries := [][]byte{
+		[]byte("ACATATAAGTAAGGTTTTACAAAC"),
+		ranseq(200, "ACGT"),
+	}
+	count := func(s, w []byte) int {
+		c := 0
+		for p := 0; p+len(w) <= len(s); p++ {
+			if string(s[p:p+len(w)]) == string(w) {
+				c++
+			}
+		}
+		return c
+	}
+	for _, ref := range refs {
+		e := NewEsa(ref, "")
+		e = e.WithSuffixLinks()
+		for _, q := range queries {
+			for _, minLength := range []int{1, 3, 6} {
+				var want []MEM
+				for _, m := range naiveMEMs(&e, q, minLength) {
+					w := q[m.QueryPos : m.QueryPos+m.Length]
+					if count(ref, w) == 1 && count(q, w) == 1 {
+						want = append(want, m)
+					}
+				}
+				if got := e.FindMUMs(q, minLength); fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Fatalf("FindMUMs(%s, %d) = %v, want %v", q, minLength, got, want)
+				}
+			}
+		}
+	}
+}
@@ -51,6 +51,36 @@
 //	//including reverse
 //	eRev := esaMatcher.NewRevEsa(data, "sais")
 //
+// Texts over other alphabets, for example proteins or arbitrary bytes, can be validated and indexed
+// with an Alphabet. Texts over more than 256 symbols, like tokenised words, are indexed by Esa16.
+//
+//	p, err := esaMatcher.NewAlphabetEsa(protein, esaMatcher.Protein, "SaSais")
+//	w := esaMatcher.NewEsa16(tokens, "SaSais")
+//
+// FASTA and multi-FASTA files, also gzip-compressed, are read by FastaReader. NewFastaEsa builds an ESA
+// from the records and maps match positions back to them.
+//
+//	records, err := esaMatcher.ReadFastaFile("genome.fa.gz", esaMatcher.FastaOptions{Upper: true})
+//	e, recordMap := esaMatcher.NewFastaEsa(records, true, "SaSais")
+//
+// An ESA is saved with Esa.WriteTo and loaded again with ReadEsa.
+// The command esa in cmd/esa builds such indices from FASTA files on the command line.
+//
+//	$ go install github.com/dadidange/esaMatcher/cmd/esa@latest
+//	$ esa build -rev -o genome.esa genome.fa.gz
+//	$ esa serve -addr localhost:8080 genome.esa
+//
+// The server answers JSON queries through the http.Handler returned by NewHandler,
+// which can also be embedded in other programs. The separate module in the directory grpc
+// offers the same queries over gRPC, see grpc/esapb/esa.proto.
+//
+// Large texts that do not fit into memory as an ESA can be indexed by an FMIndex or a CompressedEsa,
+// which samples the suffix array and keeps the lcp array and the child table in 2n bits each. The sample rate trades
+// the size of the index against the time of GetMatch and Locate.
+//
+//	c := esaMatcher.NewCompressedEsa(data, true, "SaSais", 32)
+//	match := c.GetMatch(query)
+//
 // For details about the returned struct see the documentation below.
 // Most parts of the documentation are adopted from the documentation in par_lp.
 package esaMatcher
\ No newline at end of file
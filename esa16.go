@@ -0,0 +1,63 @@
+package esaMatcher
+
+// The Esa16 type holds the ESA of a text over 16-bit symbols.
+//
+// It can be used for texts over more than 256 symbols, for example tokenised words
+// where each word is replaced by its index in a dictionary.
+// Since every value of an uint16 may be a symbol, the text is stored with every symbol
+// shifted by one and an out-of-band sentinel 0 at its end.
+type Esa16 struct {
+	s   []int32
+	sa  []int
+	lcp []int
+	cld []int
+}
+
+// Return the suffix array of Esa16.
+func (e *Esa16) Sa() []int { return e.sa }
+
+// Return the longest common prefix array of Esa16.
+func (e *Esa16) Lcp() []int { return e.lcp }
+
+// Return the child array of Esa16.
+func (e *Esa16) Cld() []int { return e.cld }
+
+// Return a copy of the sequence for Esa16 without the sentinel.
+func (e *Esa16) Sequence() []uint16 {
+	s := make([]uint16, len(e.s)-1)
+	for i := range s {
+		s[i] = uint16(e.s[i] - 1)
+	}
+	return s
+}
+
+// Initialize new ESA of text s over 16-bit symbols with given suffix array library.
+//
+// Only SaSais and SaNaive support texts of 16-bit symbols, for SaDivSufSort SaSais is used instead.
+func NewEsa16(s []uint16, saLib string) Esa16 {
+	t := make([]int32, len(s)+1)
+	for i, c := range s {
+		t[i] = int32(c) + 1
+	}
+	sa := saInt(t, 1<<16+1, saLib)
+	lcp := append(lcpOf(t, sa), -1)
+	cld := Cld(lcp)
+
+	return Esa16{t, sa, lcp, cld}
+}
+
+// Given an interval i on the ESA and a symbol c GetInterval returns the subinterval
+// of i that starts with c. See Esa.GetInterval for details.
+func (e *Esa16) GetInterval(i EsaInterval, c uint16) EsaInterval {
+	return getInterval(e.s, e.sa, e.lcp, e.cld, len(e.s)-1, i, int32(c)+1)
+}
+
+// GetMatch returns the longest prefix of the query that matches the ESA.
+// See Esa.GetMatch for details.
+func (e *Esa16) GetMatch(query []uint16) EsaInterval {
+	q := make([]int32, len(query))
+	for i, c := range query {
+		q[i] = int32(c) + 1
+	}
+	return getMatch(e.s, e.sa, e.lcp, e.cld, len(e.s)-1, q)
+}
@@ -9,11 +9,13 @@ package esaMatcher
 */
 import "C"
 import (
-	"fmt"
+	"bytes"
+	"container/heap"
 	"log"
+	"os"
 	"reflect"
+	"sort"
 	"unsafe"
-	"bytes"
 )
 
 const (
@@ -24,12 +26,19 @@ const (
 // The Esa type holds relevant properties of the ESA. 
 // All properties are initialized when the Esa is constructed and  
 // can be acessed by calling their corresponding getters. 
+// The Esa is not modified after its construction, hence its methods can be called
+// concurrently from several goroutines, see QueryPool.
 type Esa struct {
 	s          []byte
 	sa         []int
 	lcp        []int
 	cld        []int
 	strandSize int
+	alphabet   *Alphabet
+	prefix     *prefixTable
+	nav        *navigation
+	// Positions of the record separators in ascending order, see NewFastaEsa.
+	separators []int
 }
 
 // Return the suffix array of Esa.
@@ -43,6 +52,8 @@ func (e *Esa) Sequence() []byte { return e.s }
 // Return the single strand size hold by the esa. 
 // Equals len(Sequence) if the ESA was initialized w/o the reverse complement.
 func (e *Esa) StrandSize() int  { return e.strandSize }
+// Return the alphabet of the sequence.
+func (e *Esa) Alphabet() *Alphabet { return e.alphabet }
 
 // Initialize new ESA with default values
 func NewBaseEsa(s []byte) Esa{
@@ -51,60 +62,93 @@ func NewBaseEsa(s []byte) Esa{
 
 // Initialize new ESA of text t with given suffix array library.
 // Does not include the reverse complement.
+//
+// The text is terminated by the sentinel '$'. If the text itself contains '$' or 
+// any other byte that does not sort above the sentinels, out-of-band sentinels are used, 
+// see Alphabet. The text is copied, hence s is neither modified nor aliased by the ESA.
 func NewEsa(s []byte, saLib string) Esa {
-	strandSize := len(s)
-	s = append(s, '$')
+	t := make([]byte, len(s)+1)
+	copy(t, s)
+	t[len(s)] = '$'
+	return buildEsa(t, len(s), Bytes, saLib)
+}
+
+// Initialize new ESA of text s over the alphabet a with given suffix array library.
+// Does not include the reverse complement.
+//
+// An error is returned if s contains symbols that are not part of a.
+// The text is copied, so s is not modified.
+// If the text contains bytes that do not sort above the sentinels, the ESA is computed 
+// on the ranks of the symbols, see Alphabet.
+func NewAlphabetEsa(s []byte, a *Alphabet, saLib string) (Esa, error) {
+	if err := a.Validate(s); err != nil {
+		return Esa{}, err
+	}
+	t := make([]byte, len(s)+1)
+	copy(t, s)
+	t[len(s)] = '$'
+	return buildEsa(t, len(s), a, saLib), nil
+}
 
-	sa := Sa(s, saLib)
-	lcp := Lcp(s, sa)
-	// Add last element to lcp if necessary
-	if lcp[len(lcp)-1] != -1 {
-		lcp = append(lcp, -1)
+// Compute the arrays of the ESA for the text t that is already terminated by its sentinels.
+//
+// The sentinels are used in-band if all other bytes of t sort above them. 
+// Otherwise t is encoded by the ranks of the alphabet a and the sentinels are stored out-of-band.
+func buildEsa(t []byte, strandSize int, a *Alphabet, saLib string) Esa {
+	var sa, lcp []int
+	if byteSentinels(t, strandSize) {
+		sa = Sa(t, saLib)
+		lcp = Lcp(t, sa)
+	} else {
+		enc := a.encode(t, strandSize)
+		sa = saInt(enc, a.Size()+2, saLib)
+		lcp = lcpOf(enc, sa)
 	}
+	lcp = append(lcp, -1)
 	cld := Cld(lcp)
 
-	return Esa{s, sa, lcp, cld, strandSize}
+	e := Esa{s: t, sa: sa, lcp: lcp, cld: cld, strandSize: strandSize, alphabet: a}
+	if debugValidate {
+		if err := e.Validate(ValidateFull); err != nil {
+			log.Fatalf("esa construction with saLib = %s failed: %v\n", saLib, err)
+		}
+	}
+	return e
+}
+
+// Report whether all bytes of t except the sentinels at strandSize and at 
+// the end sort above the sentinels '#' and '$'.
+func byteSentinels(t []byte, strandSize int) bool {
+	n := len(t) - 1
+	for i, c := range t[:n] {
+		if c <= '$' && i != strandSize {
+			return false
+		}
+	}
+	return true
 }
 
 //Initialize a new ESA of text t that also includes the reverse complement.
+//
+// The strands are separated by the sentinel '#' and terminated by '$', 
+// see NewEsa for the handling of sentinels that collide with the text.
 func NewRevEsa(s []byte, saLib string) Esa {
 	l := len(s)
-	s = append(s, append([]byte{'#'}, RevComp(s)...)...)
-	esa := NewEsa(s, saLib)
-	esa.strandSize = l
-	return esa
+	t := make([]byte, 2*l+2)
+	copy(t, s)
+	t[l] = '#'
+	copy(t[l+1:], RevComp(s))
+	t[2*l+1] = '$'
+	return buildEsa(t, l, Bytes, saLib)
 }
 
 // Print the ESA to stdout. 
+// The first numSeq rows are printed, all rows if numSeq is 0.
+//
+// Deprecated: Print does not report write errors. Use WriteTable instead, which writes
+// to any io.Writer and supports row ranges, truncated suffixes and further columns.
 func (e *Esa) Print(numSeq int) {
-	sa := e.Sa()
-	lcp := e.Lcp()
-
-	fmt.Print("i\t")
-	fmt.Print("SA\t")
-	fmt.Print("LCP\t")
-	fmt.Print("CLD\t")
-	fmt.Print("S[SA[i]..]\n")
-	fmt.Print("-----------------------------------\n")
-	m := len(sa)
-	if numSeq == 0 || numSeq > len(lcp) {
-		numSeq = len(lcp)
-	}
-	for i := 0; i < numSeq; i++ {
-		if i >= m {
-			fmt.Printf("%d\t", i)
-			fmt.Printf("%s\t", "-")
-			fmt.Printf("%d\t", lcp[i])
-			fmt.Printf("%d\t", e.Cld()[i])
-			fmt.Printf("%s\n", "-")
-			continue
-		}
-		fmt.Printf("%d\t", i)
-		fmt.Printf("%d\t", sa[i])
-		fmt.Printf("%d\t", lcp[i])
-		fmt.Printf("%d\t", e.Cld()[i])
-		fmt.Printf("%s\n", e.Sequence()[sa[i]:])
-	}
+	e.WriteTable(os.Stdout, TableOptions{To: numSeq})
 }
 
 // Calculate the suffix array for a text t using the given method. 
@@ -127,18 +171,49 @@ func Sa(t []byte, method string) []int {
 	return sa
 }
 
+// Calculate the suffix array for a text t of integers in [0,k) using the given method.
+// Only libsais and the naive construction support integer alphabets, 
+// for SaDivSufSort libsais is used instead.
+func saInt(t []int32, k int, method string) []int {
+	if method == "" {
+		method = defaultSa
+	}
+	var sa []int
+	if method == "SaDivSufSort" || method == "SaSais" {
+		sa = saSaisInt(t, k)
+	} else if method == "SaNaive" {
+		sa = saNaive(t)
+	} else {
+		s := "Current options are:\n\t-SaDivSufSort\n\t-SaSais\n\t-SaNaive"
+		log.Fatalf("library saLib = %s not defined to compute SA\n%s\n", method, s)
+	}
+	return sa
+}
+
 // Lcp returns the LCP-array of a given text t and the corresponding suffic array sa.
 //
 // The LCP-array contains the length of the common prefix of an element with its 
 // predecessor in the alphabetically sorted suffix array.
 func Lcp(t []byte, sa []int) []int {
+	return lcpOf(t, sa)
+}
+
+// Isa returns the inverse suffix array of the suffix array sa,
+// that is the rank of every suffix in sa.
+func Isa(sa []int) []int {
+	isa := make([]int, len(sa))
+	for i, p := range sa {
+		isa[p] = i
+	}
+	return isa
+}
+
+// Compute the LCP-array for a text over any symbol type.
+func lcpOf[T symbol](t []T, sa []int) []int {
 	//from https://github.com/EvolBioInf/esa/
 	n := len(t)
 	lcp := make([]int, n)
-	isa := make([]int, n)
-	for i := 0; i < n; i++ {
-		isa[sa[i]] = i
-	}
+	isa := Isa(sa)
 	lcp[0] = -1
 	l := 0
 	for i := 0; i < n; i++ {
@@ -211,67 +286,63 @@ func Cld(lcp []int) []int {
 	return cld
 }
 
-// RevComp returns the reverse complement of a given DNA string. 
+// RevComp returns the reverse complement of a given DNA string.
 //
-// The DNA nucleotide characters 'A','C','G' and 'T' will be translated to their counterparts 'T','G','C' and 'A'. 
+// The nucleotides 'A','C','G' and 'T' are translated to 'T','G','C' and 'A', and 'U' is
+// translated to 'A'. The IUPAC ambiguity codes are complemented as well, that is
+// R<->Y, K<->M, B<->V and D<->H, while S, W and N are their own complements.
+// Lowercase (soft-masked) characters stay lowercase.
 // Any other character will be converted to a 'N'.
+// For RNA sequences, where 'A' should be translated to 'U', use RevCompRNA.
 func RevComp(seq []byte) []byte {
-	n := len(seq)
-	revSeq := make([]byte, n)
-
-	//Reverse
-	//for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
-	//revSeq[i], revSeq[j] = seq[j], seq[i]
-	//}
-	//slower but more secure
-	for i:= 0; i<n; i++ {
-		revSeq[(n-i)-1] = seq[i]
-	}
-
-	//Complement
-	f := func (r rune) rune  {
-		switch{
-		case r == 'A':
-			return 'T'
-		case r == 'T':
-			return 'A'
-		case r == 'G':
-			return 'C'
-		case r == 'C':
-			return 'G'
-		default:
-			return 'N'
-		}
-	}
-	return  bytes.Map(f, revSeq)
+	return revCompTable(seq, &dnaComplement)
 }
 
-// This function is obsolete and will be removed in later versions
-func RevCompObs(s []byte) []byte {
-	n := len(s)
+// RevCompRNA returns the reverse complement of a given RNA string.
+//
+// It behaves like RevComp but translates 'A' to 'U' instead of 'T'.
+func RevCompRNA(seq []byte) []byte {
+	return revCompTable(seq, &rnaComplement)
+}
 
-	f := []byte("ACGTN")
-	r := []byte("TGCAN")
+// Complement tables for RevComp and RevCompRNA.
+// They are initialized once and map every byte to its complement.
+var dnaComplement, rnaComplement [256]byte
 
-	rev := make([]byte, len(s))
-	var dic [256]byte
-	for i := range dic {
-		dic[i] = byte(i)
-	}
-	for i, v := range f {
-		dic[v] = r[i]
+func init() {
+	from := []byte("ACGTURYKMBVDHSWN")
+	to := []byte("TGCAAYRMKVBHDSWN")
+	for i := range dnaComplement {
+		dnaComplement[i] = 'N'
 	}
-	for i, v := range rev {
-		rev[i] = dic[v]
+	for i, c := range from {
+		lc := c + 'a' - 'A'
+		dnaComplement[c] = to[i]
+		dnaComplement[lc] = to[i] + 'a' - 'A'
 	}
+	rnaComplement = dnaComplement
+	rnaComplement['A'] = 'U'
+	rnaComplement['a'] = 'u'
+}
 
-	//Reverse
-	for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
-		rev[i], rev[j] = dic[s[j]], dic[s[i]]
+// Reverse and complement seq in a single pass using the complement table tab.
+func revCompTable(seq []byte, tab *[256]byte) []byte {
+	n := len(seq)
+	rev := make([]byte, n)
+	for i, c := range seq {
+		rev[n-1-i] = tab[c]
 	}
 	return rev
 }
 
+// RevCompObs returns the reverse complement of s.
+//
+// Deprecated: RevCompObs is a leftover of an older implementation and
+// will be removed in later versions. Use RevComp instead.
+func RevCompObs(s []byte) []byte {
+	return RevComp(s)
+}
+
 // Wrapper for the C-Library LibDivSufSort, adopded from https://github.com/EvolBioInf/esa/. 
 // This function takes a text t and returns its suffix array SA. 
 func saDivSufSort(t []byte) []int {
@@ -325,6 +396,29 @@ func saSais(t []byte) []int {
 	return sa2
 }
 
+// Wrapper for the integer alphabet version of the C-Library Libsais. 
+// This function takes a text t with characters in [0,k) and returns its suffix array SA. 
+func saSaisInt(t []int32, k int) []int {
+	n := len(t)
+	if n == 0 {
+		return []int{}
+	}
+	ct := (*C.int32_t)(unsafe.Pointer(&t[0]))
+	csa := (*C.int32_t)(C.malloc(C.size_t(n * C.sizeof_int32_t)))
+	defer C.free(unsafe.Pointer(csa))
+	err := int(C.libsais_int(ct, csa, C.int32_t(n), C.int32_t(k), C.int32_t(0)))
+	if err != 0 {
+		log.Fatalf("libsais_int failed with code %d\n", err)
+	}
+
+	sa := unsafe.Slice((*int32)(unsafe.Pointer(csa)), n)
+	sa2 := make([]int, n)
+	for i, v := range sa {
+		sa2[i] = int(v)
+	}
+	return sa2
+}
+
 // The type EsaInterval represents an interval inside our ESA. 
 //
 // It contains an index for its starting and ending position. 
@@ -348,7 +442,7 @@ func (i *EsaInterval)Mid() int{return i.mid}
 // length of the lcp at mid or match length
 func (i *EsaInterval)L() int{return i.l}
 
-// Initialise new EsaInterval using the starting and ending indices and the esa on which the interval lies. 
+// Interval returns the EsaInterval of the ESA that starts and ends at the given indices.
 //
 // Concept
 //
@@ -364,23 +458,78 @@ func (i *EsaInterval)L() int{return i.l}
 // In this case we can make use of CLD[h].R that points to the next minimum of {h,j}.
 // Since {i,j} must be a child of {h,j} we can follow the right pointers until we will 
 // eventually arrive at the start index of {i,j}.
+func (e *Esa) Interval(start, end int) EsaInterval {
+	return newEsaInterval(start, end, e.lcp, e.cld)
+}
+
+// Root returns the interval of the ESA that contains all suffixes.
+func (e *Esa) Root() EsaInterval {
+	return newEsaInterval(0, len(e.s)-1, e.lcp, e.cld)
+}
+
+// Positions returns the start positions of the suffixes in the interval i in ascending order.
+// If n >= 0, only the n smallest positions are returned. They are selected with a heap
+// of n positions, so that large intervals are neither copied nor sorted.
+func (e *Esa) Positions(i EsaInterval, n int) []int {
+	if i.start < 0 || i.end < i.start {
+		return nil
+	}
+	sa := e.sa[i.start : i.end+1]
+	if n < 0 || n >= len(sa) {
+		pos := append([]int{}, sa...)
+		sort.Ints(pos)
+		return pos
+	}
+	h := maxHeap(append([]int{}, sa[:n]...))
+	heap.Init(&h)
+	for _, p := range sa[n:] {
+		if n > 0 && p < h[0] {
+			h[0] = p
+			heap.Fix(&h, 0)
+		}
+	}
+	sort.Ints(h)
+	return h
+}
+
+// A maxHeap of positions, see container/heap.
+type maxHeap []int
+
+func (h maxHeap) Len() int            { return len(h) }
+func (h maxHeap) Less(i, j int) bool  { return h[i] > h[j] }
+func (h maxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x interface{}) { *h = append(*h, x.(int)) }
+func (h *maxHeap) Pop() interface{} {
+	x := (*h)[len(*h)-1]
+	*h = (*h)[:len(*h)-1]
+	return x
+}
+
+// Initialise new EsaInterval using the starting and ending indices and the esa on which the interval lies. 
+//
+// Deprecated: NewEsaInterval copies the Esa on every call. Use Esa.Interval instead.
 func NewEsaInterval(start, end int, e Esa) EsaInterval {
+	return e.Interval(start, end)
+}
+
+// Initialise new EsaInterval from the lcp and child array, see Esa.Interval.
+func newEsaInterval(start, end int, lcp, cld []int) EsaInterval {
 	//Check for empty, invalid or singleton interval
 	if start >= end {
 		//singleton
 		if start >= 0 {
-			return EsaInterval{start, end, start, e.lcp[end]}
+			return EsaInterval{start, end, start, lcp[end]}
 		} else {
 			//empty or invalid
 			return EmptyEsaInterval()
 		}
 	}
 
-	m := e.cld[end] //CLD.L(m+1) = cld(m)
+	m := cld[end] //CLD.L(m+1) = cld(m)
 	for m <= start {
-		m = e.cld[m]
+		m = cld[m]
 	}
-	return EsaInterval{start, end, m, e.lcp[m]}
+	return EsaInterval{start, end, m, lcp[m]}
 }
 
 // Returns a new, empty interval.
@@ -400,9 +549,22 @@ func EmptyEsaInterval() EsaInterval {
 // first local minimum.
 // We loop through the child intervals and check if any interval starts with c. 
 func (e *Esa)GetInterval(i EsaInterval, c byte) (EsaInterval){
+	if e.separators != nil && c == RecordSeparator {
+		return EmptyEsaInterval()
+	}
+	return getInterval(e.s, e.sa, e.lcp, e.cld, e.strandSize, i, c)
+}
+
+// Find the child interval of i that starts with c, see Esa.GetInterval.
+// The sentinels at the positions sep and len(s)-1 never match c.
+func getInterval[T symbol](s []T, sa, lcp, cld []int, sep int, i EsaInterval, c T) EsaInterval {
+	end := len(s) - 1
+	isChar := func(p int) bool {
+		return s[p] == c && p != end && p != sep
+	}
 	// Check Singleton Interval
 	if i.start == i.end{
-	  if(e.s[e.sa[i.start]] == c){
+	  if(isChar(sa[i.start])){
 		return i
 	  } else {
 		//Return empty interval
@@ -412,10 +574,10 @@ func (e *Esa)GetInterval(i EsaInterval, c byte) (EsaInterval){
 	lower := i.start
 	upper := i.mid
 	l := i.l
-	for e.lcp[upper] == l {
-	  if (e.s[e.sa[lower]+l] == c){
+	for lcp[upper] == l {
+	  if (isChar(sa[lower]+l)){
 		//match found
-		return NewEsaInterval(lower, upper-1, *e)
+		return newEsaInterval(lower, upper-1, lcp, cld)
 	  }
 	  //increment interval boundaries
 	  lower = upper
@@ -423,10 +585,10 @@ func (e *Esa)GetInterval(i EsaInterval, c byte) (EsaInterval){
 	  if (lower == i.end){
 		break
 	  }
-	  upper = e.cld[upper] //CLD.R(m) = cld(m)
+	  upper = cld[upper] //CLD.R(m) = cld(m)
 	}
-	if (e.s[e.sa[lower] + l] == c){
-	  return NewEsaInterval(lower, i.end, *e)
+	if (isChar(sa[lower] + l)){
+	  return newEsaInterval(lower, i.end, lcp, cld)
 	} else {
 	  return EmptyEsaInterval()
 	}
@@ -439,37 +601,70 @@ func (e *Esa)GetInterval(i EsaInterval, c byte) (EsaInterval){
 //
 // GetMatch calls GetInterval once per character at most. For every character in the
 // query we can call GetInterval with the child interval returned by the previous character.
+// If the ESA has a prefix table, see WithPrefixTable, the first characters of the query 
+// are looked up in the table instead.
+// The traversal works on the arrays of the ESA directly and does not allocate.
+// In an ESA built by NewFastaEsa, the query is only matched up to its first RecordSeparator.
 func (e *Esa)GetMatch(query []byte) EsaInterval{
-	in := NewEsaInterval(0, len(e.s)-1, *e)
-	cld := EmptyEsaInterval()
-	k := 0
+	if e.separators != nil {
+		if k := bytes.IndexByte(query, RecordSeparator); k >= 0 {
+			query = query[:k]
+		}
+	}
+	if e.prefix != nil {
+		if in, ok := e.prefix.lookup(e, query); ok {
+			return getMatchFrom(e.s, e.sa, e.lcp, e.cld, e.strandSize, in, e.prefix.k, query)
+		}
+	}
+	return getMatch(e.s, e.sa, e.lcp, e.cld, e.strandSize, query)
+}
+
+// Find the longest matching prefix of query, see Esa.GetMatch.
+// The match ends at the sentinels at the positions sep and len(s)-1.
+func getMatch[T symbol](s []T, sa, lcp, cld []int, sep int, query []T) EsaInterval {
+	return getMatchFrom(s, sa, lcp, cld, sep, newEsaInterval(0, len(s)-1, lcp, cld), 0, query)
+}
+
+// Continue the search for the longest matching prefix of query in the interval in,
+// whose suffixes all start with the first k characters of query.
+func getMatchFrom[T symbol](s []T, sa, lcp, cld []int, sep int, in EsaInterval, k int, query []T) EsaInterval {
 	m := len(query)
+	//extend the match and skip common prefixes, report if a mismatch was found
+	skip := func() bool {
+		l := in.l
+		if(in.start == in.end || l > m){
+			l = m
+		}
+		for saIdx:=sa[in.start]; k < l; k++ {
+			p := saIdx+k
+			if(p == len(s)-1 || p == sep || s[p] != query[k]){
+				in.l = k
+				return true
+			}
+		}
+		return false
+	}
+	if k > 0 && skip() {
+		return in
+	}
 	for k < m{
-		cld = e.GetInterval(in, query[k])
+		child := getInterval(s, sa, lcp, cld, sep, in, query[k])
 		//check if empty interval was returned -> no match this round
-		if (cld.start == -1 && cld.end == -1){
+		if (child.start == -1 && child.end == -1){
 			// if k > 0, there where matches in previous rounds
 			if (k == 0){
-				return cld
+				return child
 			}
 			in.l = k
 			return in
 		}
 
 		k++
-		//extend the match and skip common prefixes
-		in = cld
-		l := in.l
-		if(in.start == in.end || l > m){
-			l = m
-		}		
-		for saIdx:=e.sa[in.start]; k < l; k++ {
-			if(e.s[saIdx+k] != query[k]){
-				in.l = k
-				return in
-			}
+		in = child
+		if skip() {
+			return in
 		}
 	}
 	in.l = m
 	return in
-}
\ No newline at end of file
+}
@@ -6,6 +6,7 @@ import (
 	"index/suffixarray"
 	"math/rand"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 )
@@ -138,6 +139,61 @@ func TestGetInterval_Single(t *testing.T) {
 	}
 }
 
+func TestInterval(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		ranseq(500, "ACGT"),
+	}
+	for _, seq := range seqs {
+		e := NewRevEsa(seq, "")
+		n := len(e.Sequence())
+		if root, want := e.Root(), NewEsaInterval(0, n-1, e); root != want {
+			t.Errorf("Root() = %v, want %v", root, want)
+		}
+		stack := []EsaInterval{e.Root()}
+		for len(stack) > 0 {
+			iv := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			got := e.Interval(iv.start, iv.end)
+			if want := NewEsaInterval(iv.start, iv.end, e); got != want {
+				t.Fatalf("Interval(%d, %d) = %v, want %v", iv.start, iv.end, got, want)
+			}
+			if iv.start == iv.end {
+				continue
+			}
+			// mid is the first local minimum of the lcp values inside the interval.
+			for k := iv.start + 1; k <= iv.end; k++ {
+				if e.lcp[k] < got.l || (k < got.mid && e.lcp[k] == got.l) {
+					t.Fatalf("Interval(%d, %d) has mid %d and l %d", iv.start, iv.end, got.mid, got.l)
+				}
+			}
+			e.eachChild(got, func(c EsaInterval) { stack = append(stack, c) })
+		}
+	}
+}
+
+func TestPositions(t *testing.T) {
+	e := NewRevEsa(ranseq(500, "ACGT"), "")
+	for _, q := range []string{"A", "ACG", "GATC"} {
+		iv := e.GetMatch([]byte(q))
+		all := append([]int{}, e.sa[iv.start:iv.end+1]...)
+		sort.Ints(all)
+		for _, n := range []int{-1, 0, 1, 7, len(all), len(all) + 1} {
+			want := all
+			if n >= 0 && n < len(all) {
+				want = all[:n]
+			}
+			if got := e.Positions(iv, n); fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("Positions(%s, %d) = %v, want %v", q, n, got, want)
+			}
+		}
+	}
+	if pos := e.Positions(EmptyEsaInterval(), 3); pos != nil {
+		t.Errorf("Positions of the empty interval = %v", pos)
+	}
+}
+
 func TestGetInterval_OwnSuffixes(t *testing.T) {
 	seqs := [][]byte{
 		[]byte("ACAAACATAT"), //OhleBusch Book
@@ -261,6 +317,94 @@ func TestGetMatch(t *testing.T) {
 
 }
 
+func TestGetMatch_NoAllocs(t *testing.T) {
+	e := NewRevEsa(ranseq50KBP, "")
+	q := append(append([]byte{}, ranseq50KBP[100:600]...), ranseq(500, "ACGT")...)
+	allocs := testing.AllocsPerRun(100, func() {
+		for i := 0; i < len(q); i += 50 {
+			e.GetMatch(q[i:])
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("GetMatch allocates %.1f times per run", allocs)
+	}
+}
+
+func TestRevComp(t *testing.T) {
+	tests := []struct {
+		in, dna, rna string
+	}{
+		{"ACGT", "ACGT", "ACGU"},
+		{"AACCGGTT", "AACCGGTT", "AACCGGUU"},
+		{"acgtNn", "nNacgt", "nNacgu"},
+		{"ACGU", "ACGT", "ACGU"},
+		{"RYKMBVDHSWN", "NWSDHBVKMRY", "NWSDHBVKMRY"},
+		{"ryKmb", "vkMry", "vkMry"},
+		{"AC-GX$", "NNCNGT", "NNCNGU"},
+		{"", "", ""},
+	}
+	for _, test := range tests {
+		if got := string(RevComp([]byte(test.in))); got != test.dna {
+			t.Errorf("RevComp(%q) = %q, want %q", test.in, got, test.dna)
+		}
+		if got := string(RevCompRNA([]byte(test.in))); got != test.rna {
+			t.Errorf("RevCompRNA(%q) = %q, want %q", test.in, got, test.rna)
+		}
+	}
+
+	// The reverse complement of the reverse complement is the sequence itself.
+	seq := []byte("ACGTRYKMBVDHSWNacgtrykmbvdhswn")
+	if got := RevComp(RevComp(seq)); string(got) != string(seq) {
+		t.Errorf("RevComp is not its own inverse: %q vs. %q", got, seq)
+	}
+}
+
+func TestNewEsa_ArbitraryBytes(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("$"),
+		[]byte("#$#$"),
+		[]byte("AC$GT#AC$GT#"),
+		[]byte("hello world, hello $ and # and \n"),
+		ranseq(1000, "\x00\x01#$AC\xfe\xff"),
+		ranseq(3000, allBytes()),
+	}
+	for _, seq := range seqs {
+		for _, rev := range []bool{false, true} {
+			var e Esa
+			if rev {
+				e = NewRevEsa(seq, "")
+			} else {
+				e = NewEsa(seq, "")
+			}
+			checkLcp(t, &e)
+			checkOwnSuffixes(t, &e, seq, 0)
+			if rev {
+				checkOwnSuffixes(t, &e, RevComp(seq), len(seq)+1)
+			}
+			// A query that contains the sentinels must not match them.
+			q := append(append([]byte{}, seq...), e.Sequence()[len(seq)])
+			if m := e.GetMatch(q); m.L() > len(seq) {
+				t.Errorf("Query %q matched over the sentinel", q)
+			}
+		}
+	}
+}
+
+func TestNewEsa_NoAlias(t *testing.T) {
+	buf := []byte("ACGTACGTXXXX")
+	seq := buf[:8]
+	for _, e := range []Esa{NewEsa(seq, ""), NewRevEsa(seq, "")} {
+		if string(buf) != "ACGTACGTXXXX" {
+			t.Errorf("Construction modified the input to %q", buf)
+		}
+		e.Sequence()[0] = 'T'
+		if seq[0] != 'A' {
+			t.Errorf("Sequence aliases the input")
+		}
+		seq[0] = 'A'
+	}
+}
+
 //-----------------------------
 //Benchmarks
 //-----------------------------
@@ -397,6 +541,46 @@ func Benchmark_Esa_LibDivSufSort_Eco(b *testing.B) {
 	}
 }
 
+func Benchmark_GetMatch_Eco(b *testing.B) {
+	benchmarkGetMatch(b, NewEsa(ecoSeq, ""))
+}
+
+func Benchmark_GetMatch_Rev_Eco(b *testing.B) {
+	benchmarkGetMatch(b, NewRevEsa(ecoSeq, ""))
+}
+
+func Benchmark_GetMatch_5MBP(b *testing.B) {
+	benchmarkGetMatch(b, NewEsa(ranseq5MBP, ""))
+}
+
+func Benchmark_RevComp_50MBP(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		RevComp(ranseq50MBP)
+	}
+}
+
+// Match a fixed set of queries, half of them taken from the reference
+// and half of them random, and report the allocations.
+func benchmarkGetMatch(b *testing.B, e Esa) {
+	queries := make([][]byte, 1000)
+	s := e.Sequence()
+	for i := range queries {
+		if i%2 == 0 && len(s) > 1100 {
+			p := rand.Intn(len(s) - 1100)
+			queries[i] = s[p : p+1000]
+		} else {
+			queries[i] = ranseq(1000, "ACGT")
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, q := range queries {
+			e.GetMatch(q)
+		}
+	}
+}
+
 //-----------------------------
 // Helper Functions
 //-----------------------------
@@ -431,6 +615,28 @@ func readFile(path string) []byte {
 	return seq
 }
 
+// Check the lcp array of e against a naive comparison of neighbouring suffixes
+// that stops at the sentinels.
+func checkLcp(t *testing.T, e *Esa) {
+	t.Helper()
+	s := e.Sequence()
+	for i := 1; i < len(e.sa); i++ {
+		p, q := e.sa[i-1], e.sa[i]
+		l := 0
+		for !e.isSentinel(p+l) && !e.isSentinel(q+l) && s[p+l] == s[q+l] {
+			l++
+		}
+		if e.lcp[i] != l {
+			t.Errorf("lcp[%d] = %d, want %d for %q", i, e.lcp[i], l, s)
+			return
+		}
+		if !e.isSentinel(p+l) && (e.isSentinel(q+l) || s[p+l] > s[q+l]) {
+			t.Errorf("Suffixes %d and %d are not sorted in %q", p, q, s)
+			return
+		}
+	}
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
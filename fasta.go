@@ -0,0 +1,322 @@
+package esaMatcher
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// RecordSeparator is the byte placed between the records of a multi-FASTA file
+// when they are concatenated into the text of a single ESA.
+// It is a sentinel of the ESA, hence matches never span two records.
+const RecordSeparator = '%'
+
+// A FastaRecord holds a single sequence of a FASTA file.
+type FastaRecord struct {
+	// Header line of the record without the leading '>'.
+	Name string
+	// Sequence of the record without line breaks.
+	Seq []byte
+}
+
+// FastaOptions control how sequences are transformed while reading.
+type FastaOptions struct {
+	// Convert all lowercase (soft-masked) characters to uppercase.
+	Upper bool
+	// Replace all lowercase (soft-masked) characters by 'N'.
+	// HardMask takes precedence over Upper.
+	HardMask bool
+}
+
+// A FastaReader reads the records of a FASTA or multi-FASTA file one at a time.
+// Gzip-compressed input is detected and decompressed transparently.
+type FastaReader struct {
+	lineReader
+	opts FastaOptions
+	// Header of the next record, if already read.
+	next []byte
+}
+
+// NewFastaReader returns a FastaReader reading from r.
+func NewFastaReader(r io.Reader, opts FastaOptions) (*FastaReader, error) {
+	br, closer, err := maybeGzip(r)
+	if err != nil {
+		return nil, err
+	}
+	return &FastaReader{lineReader: lineReader{r: br, closer: closer}, opts: opts}, nil
+}
+
+// A lineReader reads lines from a possibly gzip-compressed input.
+type lineReader struct {
+	r      *bufio.Reader
+	line   int
+	closer io.Closer
+}
+
+// Read a single line without its line break. The returned slice is only valid
+// until the next call.
+func (lr *lineReader) readLine() ([]byte, error) {
+	l, err := lr.r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		// Long lines are collected from several reads.
+		buf := append([]byte{}, l...)
+		for err == bufio.ErrBufferFull {
+			l, err = lr.r.ReadSlice('\n')
+			buf = append(buf, l...)
+		}
+		l = buf
+	}
+	if err != nil && (err != io.EOF || len(l) == 0) {
+		return nil, err
+	}
+	lr.line++
+	return bytes.TrimRight(l, "\r\n"), nil
+}
+
+// Close closes the gzip stream of the reader, if any. The underlying reader is not closed.
+func (lr *lineReader) Close() error {
+	if lr.closer != nil {
+		return lr.closer.Close()
+	}
+	return nil
+}
+
+// Wrap r into a buffered reader that decompresses r if it starts with the gzip magic bytes.
+func maybeGzip(r io.Reader) (*bufio.Reader, io.Closer, error) {
+	br := bufio.NewReaderSize(r, 1<<16)
+	magic, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return bufio.NewReaderSize(zr, 1<<16), zr, nil
+	}
+	return br, nil, nil
+}
+
+// Read returns the next record. At the end of the input, io.EOF is returned.
+// Empty lines are skipped, text before the first header is an error.
+func (f *FastaReader) Read() (FastaRecord, error) {
+	var rec FastaRecord
+	header := f.next
+	f.next = nil
+	for header == nil {
+		l, err := f.readLine()
+		if err != nil {
+			return rec, err
+		}
+		if len(l) == 0 {
+			continue
+		}
+		if l[0] != '>' {
+			return rec, fmt.Errorf("fasta: line %d: expected header starting with '>'", f.line)
+		}
+		header = l
+	}
+	rec.Name = string(bytes.TrimSpace(header[1:]))
+
+	for {
+		l, err := f.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rec, err
+		}
+		if len(l) > 0 && l[0] == '>' {
+			f.next = append([]byte{}, l...)
+			break
+		}
+		if bytes.IndexByte(l, RecordSeparator) >= 0 {
+			return rec, fmt.Errorf("fasta: line %d: sequence contains the record separator %q", f.line, RecordSeparator)
+		}
+		rec.Seq = append(rec.Seq, l...)
+	}
+	if rec.Seq == nil {
+		rec.Seq = []byte{}
+	}
+	f.mask(rec.Seq)
+	return rec, nil
+}
+
+// Apply the masking options to seq.
+func (f *FastaReader) mask(seq []byte) {
+	if !f.opts.Upper && !f.opts.HardMask {
+		return
+	}
+	for i, c := range seq {
+		if c >= 'a' && c <= 'z' {
+			if f.opts.HardMask {
+				seq[i] = 'N'
+			} else {
+				seq[i] = c - 'a' + 'A'
+			}
+		}
+	}
+}
+
+// ReadFasta reads all records from r.
+func ReadFasta(r io.Reader, opts FastaOptions) ([]FastaRecord, error) {
+	f, err := NewFastaReader(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var records []FastaRecord
+	for {
+		rec, err := f.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// ReadFastaFile reads all records of the FASTA file at path, which may be gzip-compressed.
+func ReadFastaFile(path string, opts FastaOptions) ([]FastaRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadFasta(file, opts)
+}
+
+// A RecordMap maps positions in the text of an ESA built from several records
+// back to the records and their coordinates.
+type RecordMap struct {
+	names      []string
+	starts     []int
+	strandSize int
+}
+
+// Return the names of the records.
+func (m *RecordMap) Names() []string { return m.names }
+
+// Return the start positions of the records on the forward strand.
+func (m *RecordMap) Starts() []int { return m.starts }
+
+// Return the length of record i.
+func (m *RecordMap) Len(i int) int {
+	if i+1 < len(m.starts) {
+		return m.starts[i+1] - m.starts[i] - 1
+	}
+	return m.strandSize - m.starts[i]
+}
+
+// Locate returns the record that contains position pos of the text and the offset
+// of pos within the record. For positions on the reverse strand, the offset is given
+// on the forward strand of the record and reverse is true.
+// If pos is a separator or sentinel, record is -1.
+func (m *RecordMap) Locate(pos int) (record, offset int, reverse bool) {
+	if pos > m.strandSize && pos < 2*m.strandSize+1 {
+		pos = 2*m.strandSize - pos
+		reverse = true
+	} else if pos >= m.strandSize {
+		return -1, 0, false
+	}
+	record = sort.SearchInts(m.starts, pos+1) - 1
+	offset = pos - m.starts[record]
+	if offset >= m.Len(record) {
+		return -1, 0, reverse
+	}
+	return record, offset, reverse
+}
+
+// LocateMatch returns the record and the offset of a match of the given length that starts
+// at position pos of the text. For matches on the reverse strand, offset is the start of the match
+// on the forward strand of the record and reverse is true.
+// If pos is a separator or sentinel, record is -1.
+func (m *RecordMap) LocateMatch(pos, length int) (record, offset int, reverse bool) {
+	record, offset, reverse = m.Locate(pos)
+	if record != -1 && reverse {
+		offset -= length - 1
+	}
+	return record, offset, reverse
+}
+
+// NewFastaEsa initializes a new ESA of the concatenated records with the given suffix array library.
+// The records are separated by RecordSeparator. If rev is true, the reverse complement
+// is included as by NewRevEsa, with the separators kept in place.
+// The returned RecordMap translates positions of the ESA to record coordinates.
+//
+// The separators are sentinels of the ESA: the lcp array stops at them, they never match a query
+// and no match spans two records. A RecordSeparator within a record is replaced by 'N',
+// so that the separators are exactly the record boundaries of the RecordMap.
+func NewFastaEsa(records []FastaRecord, rev bool, saLib string) (Esa, *RecordMap) {
+	m := &RecordMap{}
+	l := 0
+	for i, r := range records {
+		if i > 0 {
+			l++
+		}
+		m.names = append(m.names, r.Name)
+		m.starts = append(m.starts, l)
+		l += len(r.Seq)
+	}
+	m.strandSize = l
+
+	n := l + 1
+	if rev {
+		n = 2*l + 2
+	}
+	t := make([]byte, n)
+	for i, r := range records {
+		if i > 0 {
+			t[m.starts[i]-1] = RecordSeparator
+		}
+		seq := t[m.starts[i] : m.starts[i]+len(r.Seq)]
+		copy(seq, r.Seq)
+		for k, c := range seq {
+			if c == RecordSeparator {
+				seq[k] = 'N'
+			}
+		}
+	}
+	if rev {
+		t[l] = '#'
+		copy(t[l+1:], RevComp(t[:l]))
+		for i := 1; i < len(m.starts); i++ {
+			t[2*l-(m.starts[i]-1)] = RecordSeparator
+		}
+	}
+	t[n-1] = '$'
+	e := buildEsa(t, l, Bytes, saLib)
+	if len(records) > 1 {
+		e.setSeparators()
+	}
+	return e, m
+}
+
+// Record the positions of the record separators in the text and shorten the lcp values
+// that reach beyond a separator, so that the separators act as unique sentinels.
+//
+// If two neighbouring suffixes share a separator, it is at the same distance from both.
+// Hence cutting the lcp value at the first sentinel of one of the suffixes suffices.
+func (e *Esa) setSeparators() {
+	e.separators = []int{}
+	for p, c := range e.s {
+		if c == RecordSeparator && p != e.strandSize && p != len(e.s)-1 {
+			e.separators = append(e.separators, p)
+		}
+	}
+	for i := 1; i < len(e.sa); i++ {
+		p := e.sa[i]
+		if d := e.sentinelAfter(p) - p; e.lcp[i] > d {
+			e.lcp[i] = d
+		}
+	}
+	e.cld = Cld(e.lcp)
+}
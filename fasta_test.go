@@ -0,0 +1,146 @@
+package esaMatcher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+)
+
+var testFasta = ">seq1 first record\r\nACGTacgt\r\nNNAC\r\n\n>seq2\nGGGTTT\n>empty\n>seq4\nacgtA"
+
+func TestReadFasta(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(testFasta))
+	w.Close()
+
+	tests := []struct {
+		opts FastaOptions
+		seqs []string
+	}{
+		{FastaOptions{}, []string{"ACGTacgtNNAC", "GGGTTT", "", "acgtA"}},
+		{FastaOptions{Upper: true}, []string{"ACGTACGTNNAC", "GGGTTT", "", "ACGTA"}},
+		{FastaOptions{Upper: true, HardMask: true}, []string{"ACGTNNNNNNAC", "GGGTTT", "", "NNNNA"}},
+	}
+	names := []string{"seq1 first record", "seq2", "empty", "seq4"}
+	for _, test := range tests {
+		for _, in := range [][]byte{[]byte(testFasta), gz.Bytes()} {
+			records, err := ReadFasta(bytes.NewReader(in), test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != len(names) {
+				t.Fatalf("Read %d records, want %d", len(records), len(names))
+			}
+			for i, r := range records {
+				if r.Name != names[i] || string(r.Seq) != test.seqs[i] {
+					t.Errorf("Record %d is %q:%q, want %q:%q", i, r.Name, r.Seq, names[i], test.seqs[i])
+				}
+			}
+		}
+	}
+
+	if _, err := ReadFasta(strings.NewReader("ACGT\n>seq\nACGT"), FastaOptions{}); err == nil {
+		t.Error("Sequence without header was accepted")
+	}
+	if _, err := ReadFasta(strings.NewReader(">seq\nAC%GT"), FastaOptions{}); err == nil {
+		t.Error("Sequence with a record separator was accepted")
+	}
+	long := ">long\n" + strings.Repeat("ACGT", 50000)
+	records, err := ReadFasta(strings.NewReader(long), FastaOptions{})
+	if err != nil || len(records[0].Seq) != 200000 {
+		t.Errorf("Reading a long line failed: %v", err)
+	}
+}
+
+func TestNewFastaEsa(t *testing.T) {
+	records, err := ReadFasta(strings.NewReader(testFasta), FastaOptions{Upper: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rev := range []bool{false, true} {
+		e, m := NewFastaEsa(records, rev, "")
+		if err := e.Validate(ValidateFull); err != nil {
+			t.Fatal(err)
+		}
+		for i, r := range records {
+			for k := range r.Seq {
+				rec, off, reverse := m.Locate(m.Starts()[i] + k)
+				if rec != i || off != k || reverse {
+					t.Errorf("Locate(%d) = %d, %d, %v, want %d, %d", m.Starts()[i]+k, rec, off, reverse, i, k)
+				}
+			}
+			// Matches must not span two records.
+			if i+1 < len(records) {
+				q := append(append([]byte{}, r.Seq...), RecordSeparator)
+				q = append(q, records[i+1].Seq...)
+				if match := e.GetMatch(q); match.L() > len(r.Seq) {
+					t.Errorf("Match of record %d spans the separator", i)
+				}
+			}
+			if len(r.Seq) == 0 {
+				continue
+			}
+			if !rev {
+				continue
+			}
+			// The reverse complement of a record is found on the reverse strand.
+			rc := RevComp(r.Seq)
+			match := e.GetMatch(rc)
+			if match.L() != len(rc) {
+				t.Errorf("Reverse complement of record %d not found", i)
+			}
+			found := false
+			for k := match.Start(); k <= match.End(); k++ {
+				rec, off, reverse := m.Locate(e.Sa()[k] + len(rc) - 1)
+				found = found || (rec == i && off == 0 && reverse)
+			}
+			if !found {
+				t.Errorf("Reverse complement of record %d not located", i)
+			}
+			found = false
+			for k := match.Start(); k <= match.End(); k++ {
+				rec, off, reverse := m.LocateMatch(e.Sa()[k], len(rc))
+				found = found || (rec == i && off == 0 && reverse)
+			}
+			if !found {
+				t.Errorf("LocateMatch did not find the reverse complement of record %d", i)
+			}
+		}
+		// The separators are sentinels for all queries.
+		if pos, err := e.SearchRegexp("AC.GGG"); err != nil || len(pos) != 0 {
+			t.Errorf("SearchRegexp(AC.GGG) = %v, %v, want no match across records", pos, err)
+		}
+		sep := m.Starts()[1] - 1
+		if l := e.LCE(sep-2, sep-2); l != 2 {
+			t.Errorf("LCE(%d, %d) = %d, want 2", sep-2, sep-2, l)
+		}
+		for _, mem := range e.FindMEMs([]byte("NNAC%GGGTTT"), 3) {
+			if mem.QueryPos <= 4 && mem.QueryPos+mem.Length > 4 {
+				t.Errorf("MEM %v spans the separator", mem)
+			}
+		}
+		var buf bytes.Buffer
+		if _, err := e.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if read, err := ReadEsa(&buf); err != nil || !read.isSentinel(sep) {
+			t.Errorf("ReadEsa lost the separators: %v", err)
+		}
+		if rec, _, _ := m.Locate(m.Starts()[1] - 1); rec != -1 {
+			t.Errorf("Separator located in record %d", rec)
+		}
+	}
+}
+
+func TestNewFastaEsa_Separator(t *testing.T) {
+	records := []FastaRecord{{"a", []byte("AC%GT")}, {"b", []byte("GT")}}
+	e, m := NewFastaEsa(records, false, "")
+	if string(e.Sequence()) != "ACNGT%GT$" || string(records[0].Seq) != "AC%GT" {
+		t.Errorf("Sequence() = %q, want the separator within the record replaced", e.Sequence())
+	}
+	if rec, off, _ := m.Locate(2); rec != 0 || off != 2 || !e.isSentinel(5) || e.isSentinel(2) {
+		t.Errorf("Locate(2) = %d, %d, want the separators at the record boundaries", rec, off)
+	}
+}
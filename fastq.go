@@ -0,0 +1,212 @@
+package esaMatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// A FastqRecord holds a single read of a FASTQ file.
+type FastqRecord struct {
+	// Header line of the read without the leading '@'.
+	Name string
+	// Sequence of the read.
+	Seq []byte
+	// Phred quality scores of the read in ASCII encoding with offset 33.
+	Qual []byte
+}
+
+// MeanQuality returns the mean Phred quality score of the read.
+func (r *FastqRecord) MeanQuality() float64 {
+	if len(r.Qual) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, q := range r.Qual {
+		sum += int(q) - 33
+	}
+	return float64(sum) / float64(len(r.Qual))
+}
+
+// A FastqReader reads the records of a FASTQ file one at a time.
+// Each record must consist of four lines. Gzip-compressed input is detected and
+// decompressed transparently.
+type FastqReader struct {
+	lineReader
+}
+
+// NewFastqReader returns a FastqReader reading from r.
+func NewFastqReader(r io.Reader) (*FastqReader, error) {
+	br, closer, err := maybeGzip(r)
+	if err != nil {
+		return nil, err
+	}
+	return &FastqReader{lineReader{r: br, closer: closer}}, nil
+}
+
+// Read returns the next read. At the end of the input, io.EOF is returned.
+func (f *FastqReader) Read() (FastqRecord, error) {
+	var rec FastqRecord
+	l, err := f.readLine()
+	for err == nil && len(l) == 0 {
+		l, err = f.readLine()
+	}
+	if err != nil {
+		return rec, err
+	}
+	if l[0] != '@' {
+		return rec, fmt.Errorf("fastq: line %d: expected header starting with '@'", f.line)
+	}
+	rec.Name = string(l[1:])
+
+	var lines [3][]byte
+	for i := range lines {
+		l, err = f.readLine()
+		if err == io.EOF {
+			return rec, fmt.Errorf("fastq: line %d: unexpected end of record %s", f.line, rec.Name)
+		}
+		if err != nil {
+			return rec, err
+		}
+		lines[i] = append([]byte{}, l...)
+	}
+	if len(lines[1]) == 0 || lines[1][0] != '+' {
+		return rec, fmt.Errorf("fastq: line %d: expected separator starting with '+'", f.line-1)
+	}
+	if len(lines[0]) != len(lines[2]) {
+		return rec, fmt.Errorf("fastq: line %d: quality and sequence of %s differ in length", f.line, rec.Name)
+	}
+	rec.Seq, rec.Qual = lines[0], lines[2]
+	return rec, nil
+}
+
+// SearchMode selects how reads are matched by Esa.SearchFastq.
+type SearchMode int
+
+const (
+	// ExactSearch reports a single seed if the whole read occurs in the ESA.
+	ExactSearch SearchMode = iota
+	// SeedSearch reports a chain of seeds that covers the read: Starting at the
+	// beginning, the longest match is searched. The next search starts one position
+	// behind the end of the match.
+	SeedSearch
+)
+
+// FastqSearchOptions control Esa.SearchFastq.
+type FastqSearchOptions struct {
+	// How reads are matched.
+	Mode SearchMode
+	// Reads shorter than MinLength are skipped.
+	MinLength int
+	// Reads with a mean Phred quality below MinQuality are skipped.
+	MinQuality float64
+	// Seeds shorter than MinSeedLength are not reported.
+	MinSeedLength int
+	// Number of reads that are held in memory and matched at once, 1024 by default.
+	BatchSize int
+	// Number of goroutines that match the reads of a batch concurrently, 1 by default.
+	Workers int
+}
+
+// A Seed is a match between a read and the ESA.
+type Seed struct {
+	// Position of the seed in the read.
+	QueryPos int
+	// Interval of the ESA whose suffixes start with the seed.
+	// The length of the seed is stored in Interval.L().
+	Interval EsaInterval
+}
+
+// A ReadResult holds the matches found for a single read.
+type ReadResult struct {
+	// Number of the read in the file, starting at 0.
+	Number int
+	// The read itself.
+	Read FastqRecord
+	// Skipped is true if the read was filtered by length or quality.
+	Skipped bool
+	// Seeds found for the read.
+	Seeds []Seed
+}
+
+// SearchFastq streams the reads of the FASTQ data in r, matches each read against the ESA
+// and calls emit with the results in the order of the reads.
+//
+// The reads are read in batches of opts.BatchSize, hence the memory used does not depend
+// on the size of the input. The reads of a batch are matched one after another, or by
+// opts.Workers goroutines concurrently, before their results are emitted.
+// If emit returns an error, the search stops and returns this error.
+func (e *Esa) SearchFastq(r io.Reader, opts FastqSearchOptions, emit func(ReadResult) error) error {
+	fr, err := NewFastqReader(r)
+	if err != nil {
+		return err
+	}
+	defer fr.Close()
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1024
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	batch := make([]ReadResult, 0, opts.BatchSize)
+	number := 0
+	for done := false; !done; {
+		batch = batch[:0]
+		for len(batch) < opts.BatchSize {
+			rec, err := fr.Read()
+			if errors.Is(err, io.EOF) {
+				done = true
+				break
+			}
+			if err != nil {
+				return err
+			}
+			batch = append(batch, ReadResult{Number: number, Read: rec})
+			number++
+		}
+		parallelFor(context.Background(), opts.Workers, len(batch), func(i int) {
+			e.searchRead(&batch[i], &opts)
+		})
+		for _, res := range batch {
+			if err := emit(res); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Apply the filters of opts to the read of res and search its seeds.
+func (e *Esa) searchRead(res *ReadResult, opts *FastqSearchOptions) {
+	read := &res.Read
+	if len(read.Seq) < opts.MinLength || read.MeanQuality() < opts.MinQuality {
+		res.Skipped = true
+		return
+	}
+	res.Seeds = e.Seeds(read.Seq, opts.Mode, opts.MinSeedLength)
+}
+
+// Seeds returns the seeds of query against the ESA that are at least minLength long.
+// See SearchMode for the search modes.
+func (e *Esa) Seeds(query []byte, mode SearchMode, minLength int) []Seed {
+	if mode == ExactSearch {
+		m := e.GetMatch(query)
+		if len(query) == 0 || m.start == -1 || m.l < len(query) || m.l < minLength {
+			return nil
+		}
+		return []Seed{{0, m}}
+	}
+	var seeds []Seed
+	for i := 0; i < len(query); {
+		m := e.GetMatch(query[i:])
+		if m.start != -1 && m.l >= minLength {
+			seeds = append(seeds, Seed{i, m})
+		}
+		if m.start == -1 {
+			m.l = 0
+		}
+		i += m.l + 1
+	}
+	return seeds
+}
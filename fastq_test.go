@@ -0,0 +1,81 @@
+package esaMatcher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSearchFastq(t *testing.T) {
+	ref := []byte("TCTAATGAATATGTAGGATACGAATCGGATGATTCTCGAGCATCAGCATGGA" +
+		"GGACTCCATGTGTTAAGTCACCGACTGCGTGCCACCTGCGTCTTCGAAACGG")
+	e := NewEsa(ref, "")
+
+	reads := []struct {
+		seq, qual string
+		skipped   bool
+		exact     bool
+	}{
+		{"GGATACGAATCGG", "IIIIIIIIIIIII", false, true},
+		{"GGATACGAATCGGTTTTTTTCCATGTGTTAAG", "IIIIIIIIIIIIIIIIIIIIIIIIIIIIIIII", false, false},
+		{"GGATACGAATCGG", "#############", true, false},
+		{"GGA", "III", true, false},
+		{"AAAAAAAAAAAAA", "IIIIIIIIIIIII", false, false},
+	}
+	var fq strings.Builder
+	for i, r := range reads {
+		fmt.Fprintf(&fq, "@read%d\n%s\n+\n%s\n", i, r.seq, r.qual)
+	}
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(fq.String()))
+	w.Close()
+
+	for _, in := range [][]byte{[]byte(fq.String()), gz.Bytes()} {
+		for _, mode := range []SearchMode{ExactSearch, SeedSearch} {
+			opts := FastqSearchOptions{Mode: mode, MinLength: 5, MinQuality: 20, MinSeedLength: 8, BatchSize: 2, Workers: 3}
+			next := 0
+			err := e.SearchFastq(bytes.NewReader(in), opts, func(res ReadResult) error {
+				r := reads[res.Number]
+				if res.Number != next {
+					t.Errorf("Got read %d, want %d", res.Number, next)
+				}
+				next++
+				if res.Skipped != r.skipped {
+					t.Errorf("Read %d skipped = %v, want %v", res.Number, res.Skipped, r.skipped)
+				}
+				if mode == ExactSearch && (len(res.Seeds) == 1) != r.exact {
+					t.Errorf("Read %d has %d exact matches", res.Number, len(res.Seeds))
+				}
+				for _, s := range res.Seeds {
+					seed := r.seq[s.QueryPos : s.QueryPos+s.Interval.L()]
+					if s.Interval.L() < 8 || !strings.Contains(string(ref), seed) {
+						t.Errorf("Invalid seed %q for read %d", seed, res.Number)
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next != len(reads) {
+				t.Errorf("Got %d reads, want %d", next, len(reads))
+			}
+		}
+	}
+
+	seeds := e.Seeds([]byte(reads[1].seq), SeedSearch, 8)
+	if len(seeds) != 2 || seeds[0].QueryPos != 0 || seeds[1].Interval.L() < 11 {
+		t.Errorf("Unexpected seeds %v", seeds)
+	}
+
+	bad := []string{"read\nACGT\n+\nIIII\n", "@read\nACGT\n-\nIIII\n", "@read\nACGT\n+\nIII\n", "@read\nACGT\n+\n"}
+	for _, in := range bad {
+		err := e.SearchFastq(strings.NewReader(in), FastqSearchOptions{}, func(ReadResult) error { return nil })
+		if err == nil {
+			t.Errorf("Invalid FASTQ %q was accepted", in)
+		}
+	}
+}
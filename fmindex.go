@@ -0,0 +1,246 @@
+package esaMatcher
+
+import "sort"
+
+// BWT returns the Burrows-Wheeler transform of the text of the ESA, that is the character
+// preceding each suffix in the order of the suffix array. The suffix at position 0 is preceded
+// by the final sentinel.
+//
+// The sentinels appear as their bytes '#' and '$'. If the ESA uses out-of-band sentinels,
+// see Alphabet, these bytes can not be told apart from the text, see FMIndex for a BWT that
+// keeps track of the sentinels.
+func (e *Esa) BWT() []byte {
+	n := len(e.sa)
+	bwt := make([]byte, n)
+	for i, p := range e.sa {
+		if p == 0 {
+			p = n
+		}
+		bwt[i] = e.s[p-1]
+	}
+	return bwt
+}
+
+// Distance between the checkpoints of the occurrence table of the FMIndex.
+const fmOccRate = 64
+
+// An FMIndex is a compressed full-text index of a text consisting of its Burrows-Wheeler
+// transform, an occurrence table sampled every 64 rows and a suffix array sampled
+// at every SampleRate-th text position. It takes about (19/16 + σ/16)n + n⌈log₂ n⌉/(8·SampleRate) bytes
+// for a text of length n over σ distinct symbols, compared to at least 24n bytes for the ESA.
+//
+// Its rows are the rows of the suffix array of the ESA of the same text, hence Count and
+// Locate agree with the intervals of the ESA. Sentinels are never matched, including the
+// record separators of an ESA built by NewFastaEsa, hence no match spans two records.
+type FMIndex struct {
+	bwt        []byte
+	strandSize int
+	// Whether RecordSeparator is a sentinel, see Esa.FMIndex.
+	separators bool
+	// Rows that hold the final sentinel and the separator of the strands, -1 if there is none.
+	endRow, sepRow int
+	// The distinct bytes of the text, their index in symbols and the number
+	// of suffixes that start with a smaller symbol or a sentinel.
+	symbols []byte
+	code    [256]int16
+	c       []int
+	// Number of occurrences of each symbol before every checkpoint.
+	occ []uint32
+	// Suffix array samples in the order of their rows. A row is sampled if its bit is set.
+	sampleRate int
+	sampled    *bitVector
+	samples    *intVector
+}
+
+// NewFMIndex builds the FMIndex of s with the given suffix array library.
+// If rev is true, the reverse complement is included as by NewRevEsa.
+// Only the suffix array is computed, the lcp array and the child table of the ESA are not needed.
+// Every sampleRate-th text position is kept, see FMIndex.Locate.
+func NewFMIndex(s []byte, rev bool, saLib string, sampleRate int) *FMIndex {
+	t, sa := strandText(s, rev, saLib)
+	return newFMIndex(t, sa, len(s), sampleRate)
+}
+
+// Return the text of s terminated by its sentinels, including the reverse complement
+// if rev is true, and its suffix array.
+func strandText(s []byte, rev bool, saLib string) (t []byte, sa []int) {
+	l := len(s)
+	if rev {
+		t = make([]byte, 2*l+2)
+		copy(t, s)
+		t[l] = '#'
+		copy(t[l+1:], RevComp(s))
+	} else {
+		t = make([]byte, l+1)
+		copy(t, s)
+	}
+	t[len(t)-1] = '$'
+	if byteSentinels(t, l) {
+		sa = Sa(t, saLib)
+	} else {
+		sa = saInt(Bytes.encode(t, l), Bytes.Size()+2, saLib)
+	}
+	return t, sa
+}
+
+// FMIndex returns the FMIndex of the text of the ESA, see NewFMIndex.
+// If the ESA was built by NewFastaEsa, patterns that contain a RecordSeparator do not occur.
+func (e *Esa) FMIndex(sampleRate int) *FMIndex {
+	f := newFMIndex(e.s, e.sa, e.strandSize, sampleRate)
+	f.separators = e.separators != nil
+	return f
+}
+
+// Build the FMIndex from the text t, its suffix array and the position of the strand separator.
+func newFMIndex(t []byte, sa []int, strandSize, sampleRate int) *FMIndex {
+	if sampleRate < 1 {
+		sampleRate = 1
+	}
+	n := len(t)
+	f := &FMIndex{strandSize: strandSize, endRow: -1, sepRow: -1, sampleRate: sampleRate}
+	f.bwt = make([]byte, n)
+	f.sampled = newBitVector(n)
+	f.samples = newIntVector((n-1)/sampleRate+1, n-1)
+	var counts [256]int
+	for i, p := range sa {
+		switch {
+		case p == 0:
+			f.endRow = i
+		case p == strandSize+1 && strandSize < n-1:
+			f.sepRow = i
+		default:
+			counts[t[p-1]]++
+		}
+		if p == 0 {
+			f.bwt[i] = t[n-1]
+		} else {
+			f.bwt[i] = t[p-1]
+		}
+		if p%sampleRate == 0 {
+			f.sampled.set(i)
+		}
+	}
+	f.sampled.build()
+	for i, p := range sa {
+		if p%sampleRate == 0 {
+			f.samples.set(f.sampled.rank(i), p)
+		}
+	}
+
+	for i := range f.code {
+		f.code[i] = -1
+	}
+	sum := 1
+	if f.sepRow != -1 {
+		sum = 2
+	}
+	for b, k := range counts {
+		if k > 0 {
+			f.code[b] = int16(len(f.symbols))
+			f.symbols = append(f.symbols, byte(b))
+			f.c = append(f.c, sum)
+			sum += k
+		}
+	}
+
+	sigma := len(f.symbols)
+	f.occ = make([]uint32, (n/fmOccRate+1)*sigma)
+	cur := make([]uint32, sigma)
+	for i := 0; i < n; i++ {
+		if i%fmOccRate == 0 {
+			copy(f.occ[i/fmOccRate*sigma:], cur)
+		}
+		if i != f.endRow && i != f.sepRow {
+			cur[f.code[f.bwt[i]]]++
+		}
+	}
+	if n%fmOccRate == 0 {
+		copy(f.occ[n/fmOccRate*sigma:], cur)
+	}
+	return f
+}
+
+// Return the length of the indexed text including the sentinels.
+func (f *FMIndex) Len() int { return len(f.bwt) }
+
+// Return the distance between the sampled text positions.
+func (f *FMIndex) SampleRate() int { return f.sampleRate }
+
+// Return the number of occurrences of the symbol with code k in the rows before row i.
+func (f *FMIndex) occurrences(k, i int) int {
+	cp := i / fmOccRate
+	r := int(f.occ[cp*len(f.symbols)+k])
+	c := f.symbols[k]
+	for j := cp * fmOccRate; j < i; j++ {
+		if f.bwt[j] == c {
+			r++
+		}
+	}
+	// The sentinels are not counted in the occurrence table.
+	for _, s := range [2]int{f.endRow, f.sepRow} {
+		if s >= cp*fmOccRate && s < i && f.bwt[s] == c {
+			r--
+		}
+	}
+	return r
+}
+
+// Return the row of the suffix that starts one position before the suffix of row i.
+// The row of the suffix at position 0 must not be passed.
+func (f *FMIndex) lf(i int) int {
+	if i == f.sepRow {
+		// The separator is the smallest suffix.
+		return 0
+	}
+	k := int(f.code[f.bwt[i]])
+	return f.c[k] + f.occurrences(k, i)
+}
+
+// Interval returns the rows [start, end] of all suffixes that start with p by backward search.
+// If p does not occur, start is greater than end. The rows match the interval
+// of the suffix array of the ESA.
+func (f *FMIndex) Interval(p []byte) (start, end int) {
+	lo, hi := 0, len(f.bwt)
+	for i := len(p) - 1; i >= 0 && lo < hi; i-- {
+		k := int(f.code[p[i]])
+		if k < 0 || f.separators && p[i] == RecordSeparator {
+			return 0, -1
+		}
+		lo = f.c[k] + f.occurrences(k, lo)
+		hi = f.c[k] + f.occurrences(k, hi)
+	}
+	return lo, hi - 1
+}
+
+// Count returns the number of occurrences of p in O(|p|) time.
+func (f *FMIndex) Count(p []byte) int {
+	start, end := f.Interval(p)
+	return end - start + 1
+}
+
+// Locate returns the positions of all occurrences of p in ascending order.
+// Each position takes at most SampleRate-1 steps of the LF mapping.
+func (f *FMIndex) Locate(p []byte) []int {
+	start, end := f.Interval(p)
+	var pos []int
+	for i := start; i <= end; i++ {
+		pos = append(pos, f.position(i))
+	}
+	sort.Ints(pos)
+	return pos
+}
+
+// Return the size of the index in bytes.
+func (f *FMIndex) size() int {
+	return len(f.bwt) + 8*len(f.c) + 4*len(f.occ) + f.sampled.size() + f.samples.size()
+}
+
+// Return the text position of the suffix in row i.
+func (f *FMIndex) position(i int) int {
+	steps := 0
+	for !f.sampled.get(i) {
+		i = f.lf(i)
+		steps++
+	}
+	return f.samples.get(f.sampled.rank(i)) + steps
+}
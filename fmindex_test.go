@@ -0,0 +1,75 @@
+package esaMatcher
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+func TestBWT(t *testing.T) {
+	e := NewEsa([]byte("ACAAACATAT"), "") //OhleBusch Book
+	if got, want := string(e.BWT()), "TCA$ATCAAAA"; got != want {
+		t.Errorf("BWT() = %s, want %s", got, want)
+	}
+}
+
+func TestFMIndex(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		[]byte("A$C#AC$"),
+		ranseq(3000, "ACGTN"),
+	}
+	queries := [][]byte{[]byte("A"), []byte("AC"), []byte("$"), []byte("C#"), []byte("GGG"), []byte("X"), {}}
+	for i := 0; i < 50; i++ {
+		queries = append(queries, ranseq(1+i%8, "ACGT"))
+	}
+	for _, s := range seqs {
+		for _, rev := range []bool{false, true} {
+			e := NewEsa(s, "")
+			if rev {
+				e = NewRevEsa(s, "")
+			}
+			for _, rate := range []int{1, 4, 32} {
+				for _, f := range []*FMIndex{NewFMIndex(s, rev, "", rate), e.FMIndex(rate)} {
+					for _, q := range queries {
+						m := e.GetMatch(q)
+						var want []int
+						if m.Start() != -1 && m.L() == len(q) && len(q) > 0 {
+							want = append(want, e.Sa()[m.Start():m.End()+1]...)
+							sort.Ints(want)
+						}
+						if len(q) == 0 {
+							want = append([]int{}, e.Sa()...)
+							sort.Ints(want)
+						}
+						if got := f.Locate(q); fmt.Sprint(got) != fmt.Sprint(want) {
+							t.Fatalf("%q rev=%v rate=%d: Locate(%s) = %v, want %v", s, rev, rate, q, got, want)
+						}
+						if got := f.Count(q); got != len(want) {
+							t.Errorf("Count(%s) = %d, want %d", q, got, len(want))
+						}
+						if start, end := f.Interval(q); len(want) > 0 && len(q) > 0 && (start != m.Start() || end != m.End()) {
+							t.Errorf("Interval(%s) = [%d,%d], want [%d,%d]", q, start, end, m.Start(), m.End())
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestFMIndex_Fasta(t *testing.T) {
+	records := []FastaRecord{{"a", []byte("ACGTAC")}, {"b", []byte("GGGACGT")}}
+	e, _ := NewFastaEsa(records, true, "")
+	f := e.FMIndex(4)
+	for _, q := range []string{"C%G", "%", "AC%GGG"} {
+		if start, end := f.Interval([]byte(q)); start <= end || f.Count([]byte(q)) != 0 || f.Locate([]byte(q)) != nil {
+			t.Errorf("Interval(%s) = [%d,%d], want no match across records", q, start, end)
+		}
+	}
+	m := e.GetMatch([]byte("ACG"))
+	if start, end := f.Interval([]byte("ACG")); start != m.Start() || end != m.End() {
+		t.Errorf("Interval(ACG) = [%d,%d], want [%d,%d]", start, end, m.Start(), m.End())
+	}
+}
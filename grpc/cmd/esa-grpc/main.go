@@ -0,0 +1,62 @@
+// Command esa-grpc serves queries against an index written by esa build over gRPC.
+//
+// Usage
+//
+// 	esa-grpc [-addr localhost:9090] [-links] <index>
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	esa "github.com/dadidange/esaMatcher"
+	esagrpc "github.com/dadidange/esaMatcher/grpc"
+	"github.com/dadidange/esaMatcher/grpc/esapb"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:9090", "listen on `address`")
+	links := flag.Bool("links", false, "build suffix links for fast MEM queries, about 3n additional integers")
+	var opts esagrpc.Options
+	flag.IntVar(&opts.MaxQueryBytes, "max-query", 1<<20, "maximum query length in `bytes`")
+	flag.IntVar(&opts.MaxPositions, "max-positions", 1000, "maximum number of positions per query")
+	flag.IntVar(&opts.MaxMEMs, "max-mems", 1000, "maximum number of MEMs per query")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: esa-grpc [flags] <index>\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	log.SetPrefix("esa-grpc: ")
+	log.SetFlags(0)
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	e, m, err := esa.ReadIndex(bufio.NewReader(f))
+	if err != nil {
+		log.Fatal(err)
+	}
+	f.Close()
+	if *links {
+		e = e.WithSuffixLinks()
+	}
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	esapb.RegisterEsaServiceServer(srv, esagrpc.NewServer(&e, m, opts))
+	log.Printf("serving %s on %s", flag.Arg(0), lis.Addr())
+	log.Fatal(srv.Serve(lis))
+}
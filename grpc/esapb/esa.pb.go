@@ -0,0 +1,579 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: esa.proto
+
+package esapb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type MatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         []byte                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchRequest) Reset() {
+	*x = MatchRequest{}
+	mi := &file_esa_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchRequest) ProtoMessage() {}
+
+func (x *MatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_esa_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchRequest.ProtoReflect.Descriptor instead.
+func (*MatchRequest) Descriptor() ([]byte, []int) {
+	return file_esa_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MatchRequest) GetQuery() []byte {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+type MatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Length of the longest matching prefix, 0 if no character matches.
+	Length int64 `protobuf:"varint,1,opt,name=length,proto3" json:"length,omitempty"`
+	// Interval of the suffixes that start with the prefix, -1 if no character matches.
+	Start int64 `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End   int64 `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	// Number of occurrences of the prefix.
+	Count         int64 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchResponse) Reset() {
+	*x = MatchResponse{}
+	mi := &file_esa_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchResponse) ProtoMessage() {}
+
+func (x *MatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_esa_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchResponse.ProtoReflect.Descriptor instead.
+func (*MatchResponse) Descriptor() ([]byte, []int) {
+	return file_esa_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MatchResponse) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *MatchResponse) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *MatchResponse) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *MatchResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type LocateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query []byte                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Maximum number of reported hits, limited by the server.
+	MaxPositions  int32 `protobuf:"varint,2,opt,name=max_positions,json=maxPositions,proto3" json:"max_positions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocateRequest) Reset() {
+	*x = LocateRequest{}
+	mi := &file_esa_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocateRequest) ProtoMessage() {}
+
+func (x *LocateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_esa_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocateRequest.ProtoReflect.Descriptor instead.
+func (*LocateRequest) Descriptor() ([]byte, []int) {
+	return file_esa_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LocateRequest) GetQuery() []byte {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *LocateRequest) GetMaxPositions() int32 {
+	if x != nil {
+		return x.MaxPositions
+	}
+	return 0
+}
+
+// A position in the text of the ESA, also given in record coordinates if the server has them.
+type Hit struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Pos    int64                  `protobuf:"varint,1,opt,name=pos,proto3" json:"pos,omitempty"`
+	Record string                 `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+	// Offset of the match in the record. For the reverse strand, the start of the match
+	// on the forward strand.
+	Offset        int64 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Reverse       bool  `protobuf:"varint,4,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Hit) Reset() {
+	*x = Hit{}
+	mi := &file_esa_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hit) ProtoMessage() {}
+
+func (x *Hit) ProtoReflect() protoreflect.Message {
+	mi := &file_esa_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hit.ProtoReflect.Descriptor instead.
+func (*Hit) Descriptor() ([]byte, []int) {
+	return file_esa_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Hit) GetPos() int64 {
+	if x != nil {
+		return x.Pos
+	}
+	return 0
+}
+
+func (x *Hit) GetRecord() string {
+	if x != nil {
+		return x.Record
+	}
+	return ""
+}
+
+func (x *Hit) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *Hit) GetReverse() bool {
+	if x != nil {
+		return x.Reverse
+	}
+	return false
+}
+
+type LocateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of occurrences of the whole query.
+	Count int64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	// Hits in ascending order of their position.
+	Hits          []*Hit `protobuf:"bytes,2,rep,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocateResponse) Reset() {
+	*x = LocateResponse{}
+	mi := &file_esa_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocateResponse) ProtoMessage() {}
+
+func (x *LocateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_esa_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocateResponse.ProtoReflect.Descriptor instead.
+func (*LocateResponse) Descriptor() ([]byte, []int) {
+	return file_esa_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LocateResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *LocateResponse) GetHits() []*Hit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+type MEMRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query []byte                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Minimum length of the reported MEMs, at least 1.
+	MinLength     int32 `protobuf:"varint,2,opt,name=min_length,json=minLength,proto3" json:"min_length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MEMRequest) Reset() {
+	*x = MEMRequest{}
+	mi := &file_esa_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MEMRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MEMRequest) ProtoMessage() {}
+
+func (x *MEMRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_esa_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MEMRequest.ProtoReflect.Descriptor instead.
+func (*MEMRequest) Descriptor() ([]byte, []int) {
+	return file_esa_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MEMRequest) GetQuery() []byte {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *MEMRequest) GetMinLength() int32 {
+	if x != nil {
+		return x.MinLength
+	}
+	return 0
+}
+
+type MEM struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueryPos      int64                  `protobuf:"varint,1,opt,name=query_pos,json=queryPos,proto3" json:"query_pos,omitempty"`
+	Length        int64                  `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+	Hit           *Hit                   `protobuf:"bytes,3,opt,name=hit,proto3" json:"hit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MEM) Reset() {
+	*x = MEM{}
+	mi := &file_esa_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MEM) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MEM) ProtoMessage() {}
+
+func (x *MEM) ProtoReflect() protoreflect.Message {
+	mi := &file_esa_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MEM.ProtoReflect.Descriptor instead.
+func (*MEM) Descriptor() ([]byte, []int) {
+	return file_esa_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MEM) GetQueryPos() int64 {
+	if x != nil {
+		return x.QueryPos
+	}
+	return 0
+}
+
+func (x *MEM) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *MEM) GetHit() *Hit {
+	if x != nil {
+		return x.Hit
+	}
+	return nil
+}
+
+type MEMResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mems          []*MEM                 `protobuf:"bytes,1,rep,name=mems,proto3" json:"mems,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MEMResponse) Reset() {
+	*x = MEMResponse{}
+	mi := &file_esa_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MEMResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MEMResponse) ProtoMessage() {}
+
+func (x *MEMResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_esa_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MEMResponse.ProtoReflect.Descriptor instead.
+func (*MEMResponse) Descriptor() ([]byte, []int) {
+	return file_esa_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MEMResponse) GetMems() []*MEM {
+	if x != nil {
+		return x.Mems
+	}
+	return nil
+}
+
+var File_esa_proto protoreflect.FileDescriptor
+
+const file_esa_proto_rawDesc = "" +
+	"\n" +
+	"\tesa.proto\x12\resamatcher.v1\"$\n" +
+	"\fMatchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\fR\x05query\"e\n" +
+	"\rMatchResponse\x12\x16\n" +
+	"\x06length\x18\x01 \x01(\x03R\x06length\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\x03R\x03end\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x03R\x05count\"J\n" +
+	"\rLocateRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\fR\x05query\x12#\n" +
+	"\rmax_positions\x18\x02 \x01(\x05R\fmaxPositions\"a\n" +
+	"\x03Hit\x12\x10\n" +
+	"\x03pos\x18\x01 \x01(\x03R\x03pos\x12\x16\n" +
+	"\x06record\x18\x02 \x01(\tR\x06record\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\x12\x18\n" +
+	"\areverse\x18\x04 \x01(\bR\areverse\"N\n" +
+	"\x0eLocateResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12&\n" +
+	"\x04hits\x18\x02 \x03(\v2\x12.esamatcher.v1.HitR\x04hits\"A\n" +
+	"\n" +
+	"MEMRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\fR\x05query\x12\x1d\n" +
+	"\n" +
+	"min_length\x18\x02 \x01(\x05R\tminLength\"`\n" +
+	"\x03MEM\x12\x1b\n" +
+	"\tquery_pos\x18\x01 \x01(\x03R\bqueryPos\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x03R\x06length\x12$\n" +
+	"\x03hit\x18\x03 \x01(\v2\x12.esamatcher.v1.HitR\x03hit\"5\n" +
+	"\vMEMResponse\x12&\n" +
+	"\x04mems\x18\x01 \x03(\v2\x12.esamatcher.v1.MEMR\x04mems2\xab\x02\n" +
+	"\n" +
+	"EsaService\x12E\n" +
+	"\bGetMatch\x12\x1b.esamatcher.v1.MatchRequest\x1a\x1c.esamatcher.v1.MatchResponse\x12E\n" +
+	"\x06Locate\x12\x1c.esamatcher.v1.LocateRequest\x1a\x1d.esamatcher.v1.LocateResponse\x12A\n" +
+	"\bFindMEMs\x12\x19.esamatcher.v1.MEMRequest\x1a\x1a.esamatcher.v1.MEMResponse\x12L\n" +
+	"\vMatchStream\x12\x1b.esamatcher.v1.MatchRequest\x1a\x1c.esamatcher.v1.MatchResponse(\x010\x01B,Z*github.com/dadidange/esaMatcher/grpc/esapbb\x06proto3"
+
+var (
+	file_esa_proto_rawDescOnce sync.Once
+	file_esa_proto_rawDescData []byte
+)
+
+func file_esa_proto_rawDescGZIP() []byte {
+	file_esa_proto_rawDescOnce.Do(func() {
+		file_esa_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_esa_proto_rawDesc), len(file_esa_proto_rawDesc)))
+	})
+	return file_esa_proto_rawDescData
+}
+
+var file_esa_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_esa_proto_goTypes = []any{
+	(*MatchRequest)(nil),   // 0: esamatcher.v1.MatchRequest
+	(*MatchResponse)(nil),  // 1: esamatcher.v1.MatchResponse
+	(*LocateRequest)(nil),  // 2: esamatcher.v1.LocateRequest
+	(*Hit)(nil),            // 3: esamatcher.v1.Hit
+	(*LocateResponse)(nil), // 4: esamatcher.v1.LocateResponse
+	(*MEMRequest)(nil),     // 5: esamatcher.v1.MEMRequest
+	(*MEM)(nil),            // 6: esamatcher.v1.MEM
+	(*MEMResponse)(nil),    // 7: esamatcher.v1.MEMResponse
+}
+var file_esa_proto_depIdxs = []int32{
+	3, // 0: esamatcher.v1.LocateResponse.hits:type_name -> esamatcher.v1.Hit
+	3, // 1: esamatcher.v1.MEM.hit:type_name -> esamatcher.v1.Hit
+	6, // 2: esamatcher.v1.MEMResponse.mems:type_name -> esamatcher.v1.MEM
+	0, // 3: esamatcher.v1.EsaService.GetMatch:input_type -> esamatcher.v1.MatchRequest
+	2, // 4: esamatcher.v1.EsaService.Locate:input_type -> esamatcher.v1.LocateRequest
+	5, // 5: esamatcher.v1.EsaService.FindMEMs:input_type -> esamatcher.v1.MEMRequest
+	0, // 6: esamatcher.v1.EsaService.MatchStream:input_type -> esamatcher.v1.MatchRequest
+	1, // 7: esamatcher.v1.EsaService.GetMatch:output_type -> esamatcher.v1.MatchResponse
+	4, // 8: esamatcher.v1.EsaService.Locate:output_type -> esamatcher.v1.LocateResponse
+	7, // 9: esamatcher.v1.EsaService.FindMEMs:output_type -> esamatcher.v1.MEMResponse
+	1, // 10: esamatcher.v1.EsaService.MatchStream:output_type -> esamatcher.v1.MatchResponse
+	7, // [7:11] is the sub-list for method output_type
+	3, // [3:7] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_esa_proto_init() }
+func file_esa_proto_init() {
+	if File_esa_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_esa_proto_rawDesc), len(file_esa_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_esa_proto_goTypes,
+		DependencyIndexes: file_esa_proto_depIdxs,
+		MessageInfos:      file_esa_proto_msgTypes,
+	}.Build()
+	File_esa_proto = out.File
+	file_esa_proto_goTypes = nil
+	file_esa_proto_depIdxs = nil
+}
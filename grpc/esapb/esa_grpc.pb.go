@@ -0,0 +1,242 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: esa.proto
+
+package esapb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	EsaService_GetMatch_FullMethodName    = "/esamatcher.v1.EsaService/GetMatch"
+	EsaService_Locate_FullMethodName      = "/esamatcher.v1.EsaService/Locate"
+	EsaService_FindMEMs_FullMethodName    = "/esamatcher.v1.EsaService/FindMEMs"
+	EsaService_MatchStream_FullMethodName = "/esamatcher.v1.EsaService/MatchStream"
+)
+
+// EsaServiceClient is the client API for EsaService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EsaService answers queries against a single loaded ESA.
+type EsaServiceClient interface {
+	// The longest prefix of the query that occurs in the ESA.
+	GetMatch(ctx context.Context, in *MatchRequest, opts ...grpc.CallOption) (*MatchResponse, error)
+	// The positions of the whole query.
+	Locate(ctx context.Context, in *LocateRequest, opts ...grpc.CallOption) (*LocateResponse, error)
+	// The maximal exact matches between the query and the ESA.
+	FindMEMs(ctx context.Context, in *MEMRequest, opts ...grpc.CallOption) (*MEMResponse, error)
+	// GetMatch for a stream of queries. The responses are sent in the order of the requests.
+	MatchStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MatchRequest, MatchResponse], error)
+}
+
+type esaServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEsaServiceClient(cc grpc.ClientConnInterface) EsaServiceClient {
+	return &esaServiceClient{cc}
+}
+
+func (c *esaServiceClient) GetMatch(ctx context.Context, in *MatchRequest, opts ...grpc.CallOption) (*MatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MatchResponse)
+	err := c.cc.Invoke(ctx, EsaService_GetMatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *esaServiceClient) Locate(ctx context.Context, in *LocateRequest, opts ...grpc.CallOption) (*LocateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LocateResponse)
+	err := c.cc.Invoke(ctx, EsaService_Locate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *esaServiceClient) FindMEMs(ctx context.Context, in *MEMRequest, opts ...grpc.CallOption) (*MEMResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MEMResponse)
+	err := c.cc.Invoke(ctx, EsaService_FindMEMs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *esaServiceClient) MatchStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MatchRequest, MatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EsaService_ServiceDesc.Streams[0], EsaService_MatchStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[MatchRequest, MatchResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EsaService_MatchStreamClient = grpc.BidiStreamingClient[MatchRequest, MatchResponse]
+
+// EsaServiceServer is the server API for EsaService service.
+// All implementations must embed UnimplementedEsaServiceServer
+// for forward compatibility.
+//
+// EsaService answers queries against a single loaded ESA.
+type EsaServiceServer interface {
+	// The longest prefix of the query that occurs in the ESA.
+	GetMatch(context.Context, *MatchRequest) (*MatchResponse, error)
+	// The positions of the whole query.
+	Locate(context.Context, *LocateRequest) (*LocateResponse, error)
+	// The maximal exact matches between the query and the ESA.
+	FindMEMs(context.Context, *MEMRequest) (*MEMResponse, error)
+	// GetMatch for a stream of queries. The responses are sent in the order of the requests.
+	MatchStream(grpc.BidiStreamingServer[MatchRequest, MatchResponse]) error
+	mustEmbedUnimplementedEsaServiceServer()
+}
+
+// UnimplementedEsaServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEsaServiceServer struct{}
+
+func (UnimplementedEsaServiceServer) GetMatch(context.Context, *MatchRequest) (*MatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMatch not implemented")
+}
+func (UnimplementedEsaServiceServer) Locate(context.Context, *LocateRequest) (*LocateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Locate not implemented")
+}
+func (UnimplementedEsaServiceServer) FindMEMs(context.Context, *MEMRequest) (*MEMResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindMEMs not implemented")
+}
+func (UnimplementedEsaServiceServer) MatchStream(grpc.BidiStreamingServer[MatchRequest, MatchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method MatchStream not implemented")
+}
+func (UnimplementedEsaServiceServer) mustEmbedUnimplementedEsaServiceServer() {}
+func (UnimplementedEsaServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeEsaServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EsaServiceServer will
+// result in compilation errors.
+type UnsafeEsaServiceServer interface {
+	mustEmbedUnimplementedEsaServiceServer()
+}
+
+func RegisterEsaServiceServer(s grpc.ServiceRegistrar, srv EsaServiceServer) {
+	// If the following call pancis, it indicates UnimplementedEsaServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EsaService_ServiceDesc, srv)
+}
+
+func _EsaService_GetMatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EsaServiceServer).GetMatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EsaService_GetMatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EsaServiceServer).GetMatch(ctx, req.(*MatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EsaService_Locate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EsaServiceServer).Locate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EsaService_Locate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EsaServiceServer).Locate(ctx, req.(*LocateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EsaService_FindMEMs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MEMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EsaServiceServer).FindMEMs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EsaService_FindMEMs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EsaServiceServer).FindMEMs(ctx, req.(*MEMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EsaService_MatchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EsaServiceServer).MatchStream(&grpc.GenericServerStream[MatchRequest, MatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EsaService_MatchStreamServer = grpc.BidiStreamingServer[MatchRequest, MatchResponse]
+
+// EsaService_ServiceDesc is the grpc.ServiceDesc for EsaService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EsaService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "esamatcher.v1.EsaService",
+	HandlerType: (*EsaServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMatch",
+			Handler:    _EsaService_GetMatch_Handler,
+		},
+		{
+			MethodName: "Locate",
+			Handler:    _EsaService_Locate_Handler,
+		},
+		{
+			MethodName: "FindMEMs",
+			Handler:    _EsaService_FindMEMs_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "MatchStream",
+			Handler:       _EsaService_MatchStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "esa.proto",
+}
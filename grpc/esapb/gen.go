@@ -0,0 +1,5 @@
+// Package esapb holds the protocol buffer messages and the gRPC client and server stubs
+// of the EsaService, generated from esa.proto.
+package esapb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative esa.proto
@@ -0,0 +1,149 @@
+// Package esagrpc serves queries against an ESA over gRPC, see the EsaService in esapb/esa.proto.
+//
+// It is a separate module, such that the esaMatcher library itself does not depend on gRPC.
+package esagrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	esa "github.com/dadidange/esaMatcher"
+	"github.com/dadidange/esaMatcher/grpc/esapb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Options limit the queries served by a Server. Zero values select the defaults.
+type Options struct {
+	// Maximum length of a query in bytes, 1 MiB by default.
+	MaxQueryBytes int
+	// Maximum number of hits reported by Locate, 1000 by default.
+	// The hits with the smallest positions are reported, see esa.Esa.Positions.
+	MaxPositions int
+	// Maximum number of MEMs reported by FindMEMs, 1000 by default.
+	// The MEMs of the leftmost query positions are reported, see esa.Esa.FindMEMsN.
+	MaxMEMs int
+}
+
+// A Server implements the EsaService for a single ESA.
+// Register it with esapb.RegisterEsaServiceServer.
+type Server struct {
+	esapb.UnimplementedEsaServiceServer
+	e    *esa.Esa
+	m    *esa.RecordMap
+	opts Options
+}
+
+// NewServer returns a Server that answers queries against e. If m is not nil,
+// hits are also reported in the coordinates of its records.
+// For fast MEM queries, e should support suffix links, see esa.Esa.WithSuffixLinks.
+func NewServer(e *esa.Esa, m *esa.RecordMap, opts Options) *Server {
+	if opts.MaxQueryBytes <= 0 {
+		opts.MaxQueryBytes = 1 << 20
+	}
+	if opts.MaxPositions <= 0 {
+		opts.MaxPositions = 1000
+	}
+	if opts.MaxMEMs <= 0 {
+		opts.MaxMEMs = 1000
+	}
+	return &Server{e: e, m: m, opts: opts}
+}
+
+// GetMatch returns the longest prefix of the query that occurs in the ESA.
+func (s *Server) GetMatch(ctx context.Context, req *esapb.MatchRequest) (*esapb.MatchResponse, error) {
+	if err := s.check(req.Query); err != nil {
+		return nil, err
+	}
+	return s.match(req.Query), nil
+}
+
+// Locate returns the positions of the whole query.
+func (s *Server) Locate(ctx context.Context, req *esapb.LocateRequest) (*esapb.LocateResponse, error) {
+	if err := s.check(req.Query); err != nil {
+		return nil, err
+	}
+	resp := &esapb.LocateResponse{}
+	m := s.e.GetMatch(req.Query)
+	if len(req.Query) == 0 || m.Start() == -1 || m.L() < len(req.Query) {
+		return resp, nil
+	}
+	resp.Count = int64(m.End() - m.Start() + 1)
+	max := int(req.MaxPositions)
+	if max <= 0 || max > s.opts.MaxPositions {
+		max = s.opts.MaxPositions
+	}
+	for _, p := range s.e.Positions(m, max) {
+		resp.Hits = append(resp.Hits, s.hit(p, len(req.Query)))
+	}
+	return resp, nil
+}
+
+// FindMEMs returns the maximal exact matches between the query and the ESA.
+func (s *Server) FindMEMs(ctx context.Context, req *esapb.MEMRequest) (*esapb.MEMResponse, error) {
+	if err := s.check(req.Query); err != nil {
+		return nil, err
+	}
+	resp := &esapb.MEMResponse{}
+	for _, m := range s.e.FindMEMsN(req.Query, int(req.MinLength), s.opts.MaxMEMs) {
+		resp.Mems = append(resp.Mems, &esapb.MEM{
+			QueryPos: int64(m.QueryPos),
+			Length:   int64(m.Length),
+			Hit:      s.hit(m.RefPos, m.Length),
+		})
+	}
+	return resp, nil
+}
+
+// MatchStream answers a stream of GetMatch requests in their order.
+func (s *Server) MatchStream(stream esapb.EsaService_MatchStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.check(req.Query); err != nil {
+			return err
+		}
+		if err := stream.Send(s.match(req.Query)); err != nil {
+			return err
+		}
+	}
+}
+
+// Reject queries above the size limit.
+func (s *Server) check(query []byte) error {
+	if len(query) > s.opts.MaxQueryBytes {
+		return status.Errorf(codes.InvalidArgument, "query of %d bytes exceeds the limit of %d", len(query), s.opts.MaxQueryBytes)
+	}
+	return nil
+}
+
+func (s *Server) match(query []byte) *esapb.MatchResponse {
+	m := s.e.GetMatch(query)
+	if m.Start() == -1 {
+		return &esapb.MatchResponse{Start: -1, End: -1}
+	}
+	return &esapb.MatchResponse{
+		Length: int64(m.L()),
+		Start:  int64(m.Start()),
+		End:    int64(m.End()),
+		Count:  int64(m.End() - m.Start() + 1),
+	}
+}
+
+// Return the hit of a match of the given length at position pos.
+func (s *Server) hit(pos, length int) *esapb.Hit {
+	hit := &esapb.Hit{Pos: int64(pos), Offset: int64(pos)}
+	if s.m == nil {
+		return hit
+	}
+	if r, off, rev := s.m.LocateMatch(pos, length); r != -1 {
+		hit.Record, hit.Offset, hit.Reverse = s.m.Names()[r], int64(off), rev
+	}
+	return hit
+}
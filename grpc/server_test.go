@@ -0,0 +1,111 @@
+package esagrpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	esa "github.com/dadidange/esaMatcher"
+	"github.com/dadidange/esaMatcher/grpc/esapb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Start a server on an in-process listener and return a client connected to it.
+func newClient(t *testing.T, s *Server) esapb.EsaServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	esapb.RegisterEsaServiceServer(srv, s)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return esapb.NewEsaServiceClient(conn)
+}
+
+func TestServer(t *testing.T) {
+	records := []esa.FastaRecord{{Name: "one", Seq: []byte("ACGTACGTAC")}, {Name: "two", Seq: []byte("GGTT")}}
+	e, m := esa.NewFastaEsa(records, true, "")
+	e = e.WithSuffixLinks()
+	c := newClient(t, NewServer(&e, m, Options{MaxQueryBytes: 100, MaxPositions: 2, MaxMEMs: 1}))
+	ctx := context.Background()
+
+	match, err := c.GetMatch(ctx, &esapb.MatchRequest{Query: []byte("GGTAC")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ggt := e.GetMatch([]byte("GGT"))
+	if match.Length != 3 || match.Start != int64(ggt.Start()) || match.Count != 1 {
+		t.Errorf("GetMatch = %v", match)
+	}
+
+	loc, err := c.Locate(ctx, &esapb.LocateRequest{Query: []byte("CGTA")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprintf("%d %d %s %d %d", loc.Count, len(loc.Hits), loc.Hits[0].Record, loc.Hits[0].Offset, loc.Hits[1].Offset); got != "3 2 one 1 5" {
+		t.Errorf("Locate = %v", loc)
+	}
+
+	mems, err := c.FindMEMs(ctx, &esapb.MEMRequest{Query: []byte("GAACCT"), MinLength: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mems.Mems) != 1 || mems.Mems[0].QueryPos != 1 || mems.Mems[0].Length != 4 ||
+		mems.Mems[0].Hit.Record != "two" || mems.Mems[0].Hit.Offset != 0 || !mems.Mems[0].Hit.Reverse {
+		t.Errorf("FindMEMs = %v", mems)
+	}
+
+	mems, err = c.FindMEMs(ctx, &esapb.MEMRequest{Query: []byte("ACGTT"), MinLength: 2})
+	if err != nil || len(mems.Mems) != 1 || mems.Mems[0].QueryPos != 0 {
+		t.Errorf("FindMEMs = %v, %v, want a single MEM at query position 0", mems, err)
+	}
+
+	_, err = c.GetMatch(ctx, &esapb.MatchRequest{Query: []byte(strings.Repeat("A", 101))})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetMatch of a long query returned %v, want InvalidArgument", err)
+	}
+}
+
+func TestServer_MatchStream(t *testing.T) {
+	e := esa.NewEsa([]byte("ACAAACATAT"), "")
+	c := newClient(t, NewServer(&e, nil, Options{}))
+	stream, err := c.MatchStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	queries := []string{"ACAT", "CATG", "GGG", "TAT", "AAACATATA"}
+	go func() {
+		for _, q := range queries {
+			stream.Send(&esapb.MatchRequest{Query: []byte(q)})
+		}
+		stream.CloseSend()
+	}()
+	for _, q := range queries {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := e.GetMatch([]byte(q))
+		if want.Start() == -1 {
+			want = esa.EmptyEsaInterval()
+		}
+		if resp.Start != int64(want.Start()) || resp.End != int64(want.End()) || want.Start() != -1 && resp.Length != int64(want.L()) {
+			t.Errorf("MatchStream(%s) = %v, want %v", q, resp, want)
+		}
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Errorf("stream was not closed")
+	}
+}
@@ -0,0 +1,273 @@
+package esaMatcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HandlerOptions limit the requests served by the handler of NewHandler.
+// Zero values select the defaults.
+type HandlerOptions struct {
+	// Maximum size of a request body in bytes, 1 MiB by default.
+	MaxBodyBytes int64
+	// Maximum number of queries per request, 1000 by default.
+	MaxQueries int
+	// Maximum number of positions reported per query by /locate, 1000 by default.
+	// The smallest positions are reported, see Esa.Positions.
+	MaxPositions int
+	// Maximum number of MEMs reported per query by /mems, 1000 by default.
+	// The MEMs of the leftmost query positions are reported, see Esa.FindMEMsN.
+	MaxMEMs int
+	// Maximum number of requests that are processed at once, 64 by default.
+	// Further requests are rejected with status 503.
+	MaxConcurrent int
+}
+
+// A QueryRequest is the JSON body of all query endpoints of NewHandler.
+type QueryRequest struct {
+	Queries []string `json:"queries"`
+	// Minimum length of the MEMs reported by /mems, 1 by default.
+	MinLength int `json:"minLength,omitempty"`
+	// Maximum number of positions reported by /locate, limited by HandlerOptions.MaxPositions.
+	MaxPositions int `json:"maxPositions,omitempty"`
+}
+
+// A Hit is a position in the text of the ESA. If the handler has a RecordMap,
+// the position is also given in record coordinates, see RecordMap.LocateMatch.
+type Hit struct {
+	Pos    int    `json:"pos"`
+	Record string `json:"record,omitempty"`
+	Offset int    `json:"offset"`
+	Strand string `json:"strand,omitempty"`
+}
+
+// A QueryResponse holds the results of the queries of a QueryRequest in their order.
+// Only the fields of the requested endpoint are set.
+type QueryResponse struct {
+	Results []QueryResponseItem `json:"results"`
+}
+
+// A QueryResponseItem is the result of a single query.
+type QueryResponseItem struct {
+	// Length of the longest matching prefix and its interval, set by /match.
+	Length *int `json:"length,omitempty"`
+	Start  *int `json:"start,omitempty"`
+	End    *int `json:"end,omitempty"`
+	// Number of occurrences of the whole query, set by /match, /locate and /count.
+	// For /match, it is the number of occurrences of the longest matching prefix.
+	Count *int `json:"count,omitempty"`
+	// Positions of the whole query, set by /locate.
+	Hits []Hit `json:"hits,omitempty"`
+	// Maximal exact matches, set by /mems.
+	MEMs []HitMEM `json:"mems,omitempty"`
+}
+
+// A HitMEM is a MEM together with the position of its match in record coordinates.
+type HitMEM struct {
+	QueryPos int `json:"queryPos"`
+	Length   int `json:"length"`
+	Hit
+}
+
+// NewHandler returns an http.Handler that answers queries against e with JSON.
+// If m is not nil, positions are also reported in the coordinates of its records.
+// The endpoints are
+//
+//	POST /match   the longest matching prefix of each query, see Esa.GetMatch
+//	POST /locate  the positions of each query
+//	POST /count   the number of occurrences of each query
+//	POST /mems    the maximal exact matches of each query, see Esa.FindMEMs
+//	GET  /health  status and size of the index
+//
+// The query endpoints take a QueryRequest and return a QueryResponse. Errors are
+// returned as an object with the field "error". For fast MEM queries, e should
+// support suffix links, see Esa.WithSuffixLinks.
+func NewHandler(e *Esa, m *RecordMap, opts HandlerOptions) http.Handler {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = 1 << 20
+	}
+	if opts.MaxQueries <= 0 {
+		opts.MaxQueries = 1000
+	}
+	if opts.MaxPositions <= 0 {
+		opts.MaxPositions = 1000
+	}
+	if opts.MaxMEMs <= 0 {
+		opts.MaxMEMs = 1000
+	}
+	if opts.MaxConcurrent <= 0 {
+		opts.MaxConcurrent = 64
+	}
+	h := &handler{e, m, opts, make(chan struct{}, opts.MaxConcurrent)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/match", h.query(h.match))
+	mux.HandleFunc("/locate", h.query(h.locate))
+	mux.HandleFunc("/count", h.query(h.count))
+	mux.HandleFunc("/mems", h.query(h.mems))
+	mux.HandleFunc("/health", h.health)
+	return mux
+}
+
+type handler struct {
+	e    *Esa
+	m    *RecordMap
+	opts HandlerOptions
+	// Holds a token for every request in progress.
+	slots chan struct{}
+}
+
+// Return a handler function that decodes a QueryRequest, calls f for every query
+// and encodes the QueryResponse.
+func (h *handler) query(f func(req *QueryRequest, q []byte) QueryResponseItem) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+			return
+		}
+		select {
+		case h.slots <- struct{}{}:
+			defer func() { <-h.slots }()
+		default:
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusServiceUnavailable, errors.New("too many concurrent requests"))
+			return
+		}
+		var req QueryRequest
+		dec := json.NewDecoder(&maxBytesReader{r: r.Body, n: h.opts.MaxBodyBytes})
+		if err := dec.Decode(&req); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, errBodyTooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			writeError(w, status, fmt.Errorf("invalid request: %w", err))
+			return
+		}
+		if len(req.Queries) > h.opts.MaxQueries {
+			writeError(w, http.StatusRequestEntityTooLarge,
+				fmt.Errorf("%d queries exceed the limit of %d", len(req.Queries), h.opts.MaxQueries))
+			return
+		}
+		if req.MaxPositions <= 0 || req.MaxPositions > h.opts.MaxPositions {
+			req.MaxPositions = h.opts.MaxPositions
+		}
+		if req.MinLength < 1 {
+			req.MinLength = 1
+		}
+		resp := QueryResponse{Results: make([]QueryResponseItem, len(req.Queries))}
+		for i, q := range req.Queries {
+			if r.Context().Err() != nil {
+				return
+			}
+			resp.Results[i] = f(&req, []byte(q))
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *handler) match(req *QueryRequest, q []byte) QueryResponseItem {
+	m := h.e.GetMatch(q)
+	length, count := 0, 0
+	if m.start != -1 {
+		length, count = m.l, m.end-m.start+1
+	}
+	return QueryResponseItem{Length: &length, Start: &m.start, End: &m.end, Count: &count}
+}
+
+func (h *handler) count(req *QueryRequest, q []byte) QueryResponseItem {
+	count := 0
+	if m, ok := h.exact(q); ok {
+		count = m.end - m.start + 1
+	}
+	return QueryResponseItem{Count: &count}
+}
+
+func (h *handler) locate(req *QueryRequest, q []byte) QueryResponseItem {
+	m, ok := h.exact(q)
+	if !ok {
+		count := 0
+		return QueryResponseItem{Count: &count}
+	}
+	count := m.end - m.start + 1
+	item := QueryResponseItem{Count: &count}
+	for _, p := range h.e.Positions(m, req.MaxPositions) {
+		item.Hits = append(item.Hits, h.hit(p, len(q)))
+	}
+	return item
+}
+
+func (h *handler) mems(req *QueryRequest, q []byte) QueryResponseItem {
+	item := QueryResponseItem{MEMs: []HitMEM{}}
+	for _, m := range h.e.FindMEMsN(q, req.MinLength, h.opts.MaxMEMs) {
+		item.MEMs = append(item.MEMs, HitMEM{m.QueryPos, m.Length, h.hit(m.RefPos, m.Length)})
+	}
+	return item
+}
+
+func (h *handler) health(w http.ResponseWriter, r *http.Request) {
+	status := map[string]interface{}{"status": "ok", "length": len(h.e.s)}
+	if h.m != nil {
+		status["records"] = len(h.m.names)
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// Return the interval of q if it occurs completely.
+func (h *handler) exact(q []byte) (EsaInterval, bool) {
+	m := h.e.GetMatch(q)
+	return m, len(q) > 0 && m.start != -1 && m.l == len(q)
+}
+
+// Return the hit of a match of the given length at position pos.
+func (h *handler) hit(pos, length int) Hit {
+	hit := Hit{Pos: pos, Offset: pos}
+	if h.m == nil {
+		return hit
+	}
+	r, off, rev := h.m.LocateMatch(pos, length)
+	if r == -1 {
+		return hit
+	}
+	hit.Record, hit.Offset, hit.Strand = h.m.names[r], off, "+"
+	if rev {
+		hit.Strand = "-"
+	}
+	return hit
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// errBodyTooLarge is returned by a maxBytesReader that is read beyond its limit.
+var errBodyTooLarge = errors.New("request body too large")
+
+// A maxBytesReader reads at most n bytes from r and fails with errBodyTooLarge
+// if r holds more.
+type maxBytesReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *maxBytesReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	if int64(n) <= l.n {
+		l.n -= int64(n)
+		return n, err
+	}
+	n = int(l.n)
+	l.n = 0
+	return n, errBodyTooLarge
+}
@@ -0,0 +1,130 @@
+package esaMatcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	records := []FastaRecord{{"one", []byte("ACGTACGTAC")}, {"two", []byte("GGTT")}}
+	e, m := NewFastaEsa(records, true, "")
+	e = e.WithSuffixLinks()
+	srv := httptest.NewServer(NewHandler(&e, m, HandlerOptions{MaxQueries: 3, MaxBodyBytes: 200, MaxMEMs: 1}))
+	defer srv.Close()
+
+	post := func(path, body string) (int, string) {
+		t.Helper()
+		resp, err := http.Post(srv.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, strings.TrimSpace(string(b))
+	}
+
+	for _, c := range []struct {
+		path, body string
+		status     int
+		want       string
+	}{
+		{"/count", `{"queries":["CGTA","GGTT","GGTA",""]}`, 413, `{"error":"4 queries exceed the limit of 3"}`},
+		{"/count", `{"queries":["CGTA","GGTT","GGTA"]}`, 200, `{"results":[{"count":3},{"count":1},{"count":0}]}`},
+		{"/locate", `{"queries":["CGTA","GGTT"],"maxPositions":2}`, 200,
+			`{"results":[{"count":3,"hits":[{"pos":1,"record":"one","offset":1,"strand":"+"},{"pos":5,"record":"one","offset":5,"strand":"+"}]},` +
+				`{"count":1,"hits":[{"pos":11,"record":"two","offset":0,"strand":"+"}]}]}`},
+		{"/mems", `{"queries":["GAACCT"],"minLength":4}`, 200,
+			`{"results":[{"mems":[{"queryPos":1,"length":4,"pos":16,"record":"two","offset":0,"strand":"-"}]}]}`},
+		{"/mems", `{"queries":["ACGTT"],"minLength":2}`, 200,
+			`{"results":[{"mems":[{"queryPos":0,"length":4,"pos":27,"record":"one","offset":0,"strand":"-"}]}]}`},
+		{"/count", `{"queries":`, 400, ``},
+		{"/count", `{"queries":["` + strings.Repeat("A", 300) + `"]}`, 413, ``},
+	} {
+		status, body := post(c.path, c.body)
+		if status != c.status || c.want != "" && body != c.want {
+			t.Errorf("POST %s %s = %d %s, want %d %s", c.path, c.body, status, body, c.status, c.want)
+		}
+	}
+
+	status, body := post("/match", `{"queries":["GGTAC","N"]}`)
+	var resp QueryResponse
+	if err := json.Unmarshal([]byte(body), &resp); status != 200 || err != nil {
+		t.Fatalf("POST /match = %d %s", status, body)
+	}
+	m0, m1 := resp.Results[0], resp.Results[1]
+	ggt := e.GetMatch([]byte("GGT"))
+	if *m0.Length != 3 || *m0.Start != ggt.Start() || *m0.Count != ggt.End()-ggt.Start()+1 ||
+		*m1.Length != 0 || *m1.Start != -1 {
+		t.Errorf("POST /match = %s", body)
+	}
+
+	res, err := http.Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if want := fmt.Sprintf(`{"length":%d,"records":2,"status":"ok"}`, len(e.Sequence())); strings.TrimSpace(string(b)) != want {
+		t.Errorf("GET /health = %s, want %s", b, want)
+	}
+	if res, err := http.Get(srv.URL + "/count"); err != nil || res.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /count did not fail with 405")
+	}
+}
+
+func TestHandler_Concurrency(t *testing.T) {
+	e := NewEsa([]byte("ACGTACGTAC"), "")
+	h := NewHandler(&e, nil, HandlerOptions{MaxConcurrent: 1})
+
+	// The first request holds the only slot while it reads its body.
+	pr, pw := io.Pipe()
+	body := &signalReader{r: pr, started: make(chan struct{})}
+	done := make(chan int, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", "/count", body))
+		done <- rec.Code
+	}()
+	select {
+	case <-body.started:
+	case code := <-done:
+		pw.CloseWithError(errors.New("first request failed"))
+		t.Fatalf("first request returned %d before reading its body", code)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/count", strings.NewReader(`{"queries":["A"]}`)))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("second request returned %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	go func() {
+		pw.Write([]byte(`{"queries":["ACG"]}`))
+		pw.Close()
+	}()
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("first request returned %d", code)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/locate", strings.NewReader(`{"queries":["ACG"]}`)))
+	if want := `{"results":[{"count":2,"hits":[{"pos":0,"offset":0},{"pos":4,"offset":4}]}]}`; strings.TrimSpace(rec.Body.String()) != want {
+		t.Errorf("POST /locate without records = %s, want %s", rec.Body.String(), want)
+	}
+}
+
+// A signalReader closes started on the first call of Read.
+type signalReader struct {
+	r       io.Reader
+	once    sync.Once
+	started chan struct{}
+}
+
+func (s *signalReader) Read(p []byte) (int, error) {
+	s.once.Do(func() { close(s.started) })
+	return s.r.Read(p)
+}
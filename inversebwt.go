@@ -0,0 +1,94 @@
+package esaMatcher
+
+import "errors"
+
+// LFMapping returns the LF mapping of the Burrows-Wheeler transform bwt, that is for every
+// row i the row of the suffix that starts one position before the suffix of row i.
+// The rows are ordered by the bytes of bwt, as for texts with in-band sentinels.
+//
+// Concept
+//
+// The k-th occurrence of a character c in the BWT precedes the k-th smallest suffix that starts
+// with c, hence LF(i) = C[c] + Occ(c, i) for c = bwt[i], where C[c] is the number of characters
+// smaller than c and Occ(c, i) the number of occurrences of c in bwt[0..i-1].
+func LFMapping(bwt []byte) []int {
+	var c [256]int
+	for _, b := range bwt {
+		c[b]++
+	}
+	sum := 0
+	for b, k := range c {
+		c[b] = sum
+		sum += k
+	}
+	lf := make([]int, len(bwt))
+	for i, b := range bwt {
+		lf[i] = c[b]
+		c[b]++
+	}
+	return lf
+}
+
+// InverseBWT returns the text of the Burrows-Wheeler transform bwt as returned by Esa.BWT
+// for ESAs with in-band sentinels, that is the text including the separator '#' of
+// NewRevEsa and the final sentinel '$'.
+//
+// An error is returned if bwt does not hold exactly one '$' and at most one '#', or if
+// it is not the transform of a single text. Transforms of ESAs with out-of-band sentinels
+// can not be inverted from their bytes alone, use FMIndex.Text instead.
+func InverseBWT(bwt []byte) ([]byte, error) {
+	n := len(bwt)
+	var ends, seps int
+	for _, b := range bwt {
+		switch b {
+		case '$':
+			ends++
+		case '#':
+			seps++
+		}
+	}
+	if ends != 1 || seps > 1 {
+		return nil, errors.New("bwt does not hold exactly one '$' and at most one '#'")
+	}
+	lf := LFMapping(bwt)
+	// The suffix "$" follows the suffix "#..." if there is one.
+	end := seps
+	t := make([]byte, n)
+	t[n-1] = '$'
+	i := end
+	for k := n - 2; k >= 0; k-- {
+		t[k] = bwt[i]
+		i = lf[i]
+		if i == end {
+			return nil, errors.New("bwt is not the transform of a single text")
+		}
+	}
+	return t, nil
+}
+
+// LF returns the row of the suffix that starts one position before the suffix of row i.
+// For the row of the suffix at position 0, the row of the final sentinel is returned.
+func (f *FMIndex) LF(i int) int {
+	if i == f.endRow {
+		if f.sepRow != -1 {
+			return 1
+		}
+		return 0
+	}
+	return f.lf(i)
+}
+
+// Text reconstructs the indexed text including its sentinels in O(n) time
+// by following the LF mapping backwards from the final sentinel.
+// It works for texts with out-of-band sentinels as well.
+func (f *FMIndex) Text() []byte {
+	n := len(f.bwt)
+	t := make([]byte, n)
+	i := f.LF(f.endRow)
+	t[n-1] = f.bwt[f.endRow]
+	for k := n - 2; k >= 0; k-- {
+		t[k] = f.bwt[i]
+		i = f.LF(i)
+	}
+	return t
+}
@@ -0,0 +1,69 @@
+package esaMatcher
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestInverseBWT(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		[]byte("A"),
+		{},
+		ranseq(3000, "ACGTN"),
+	}
+	for _, s := range seqs {
+		for _, e := range []Esa{NewEsa(s, ""), NewRevEsa(s, "")} {
+			bwt := e.BWT()
+			isa := Isa(e.Sa())
+			for i, r := range LFMapping(bwt) {
+				p := e.Sa()[i] - 1
+				if p < 0 {
+					p = len(bwt) - 1
+				}
+				if r != isa[p] {
+					t.Fatalf("LFMapping()[%d] = %d, want %d", i, r, isa[p])
+				}
+			}
+			text, err := InverseBWT(bwt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(text, e.Sequence()) {
+				t.Errorf("InverseBWT(%s) = %s, want %s", bwt, text, e.Sequence())
+			}
+		}
+	}
+	for _, bwt := range []string{"ACGT", "A$$", "#A#$", "AA$A"} {
+		if _, err := InverseBWT([]byte(bwt)); err == nil {
+			t.Errorf("InverseBWT(%s) did not fail", bwt)
+		}
+	}
+}
+
+func TestFMIndex_Text(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("A$C#AC$"),
+		[]byte("a|\x00"),
+		ranseq(3000, "ACGTN"),
+	}
+	for _, s := range seqs {
+		for _, e := range []Esa{NewEsa(s, ""), NewRevEsa(s, "")} {
+			f := e.FMIndex(8)
+			if text := f.Text(); !bytes.Equal(text, e.Sequence()) {
+				t.Errorf("Text() = %q, want %q", text, e.Sequence())
+			}
+			isa := Isa(e.Sa())
+			for i, p := range e.Sa() {
+				if p == 0 {
+					p = len(e.Sa())
+				}
+				if got := f.LF(i); got != isa[p-1] {
+					t.Fatalf("LF(%d) = %d, want %d", i, got, isa[p-1])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package esaMatcher
+
+import "fmt"
+
+// A KmerCount is a distinct k-mer of the ESA together with its number of occurrences.
+type KmerCount struct {
+	// The k-mer. It shares its memory with the sequence of the ESA.
+	Kmer []byte
+	// Number of occurrences of the k-mer.
+	Count int
+}
+
+// EachKmer calls f for every distinct k-mer of the ESA in lexicographic order together
+// with its number of occurrences. K-mers that span a sentinel, a RecordSeparator
+// or an 'N' are skipped. The k-mer passed to f shares its memory with the sequence
+// of the ESA and must not be modified.
+//
+// If canonical is true, a k-mer and its reverse complement are counted together under
+// the lexicographically smaller of both. This requires an ESA over both strands, as built by
+// NewRevEsa or NewFastaEsa with rev set, where every occurrence on one strand is
+// an occurrence of the reverse complement on the other.
+//
+// Implementation
+//
+// All suffixes that start with the same k-mer form a run of the suffix array
+// with lcp values of at least k. The first k characters of each run are checked once.
+func (e *Esa) EachKmer(k int, canonical bool, f func(kmer []byte, count int)) error {
+	if k < 1 {
+		return fmt.Errorf("k-mer length %d is less than 1", k)
+	}
+	if canonical && e.strandSize == len(e.s)-1 {
+		return fmt.Errorf("canonical k-mers need an ESA of both strands")
+	}
+	n := len(e.sa)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && e.lcp[j] >= k {
+			j++
+		}
+		if kmer, ok := e.kmerAt(e.sa[i], k); ok {
+			count := j - i
+			if canonical {
+				switch c := compareRevComp(kmer); {
+				case c > 0:
+					kmer = nil
+				case c == 0:
+					// A palindrome occurs on both strands at the same position.
+					count /= 2
+				}
+			}
+			if kmer != nil {
+				f(kmer, count)
+			}
+		}
+		i = j
+	}
+	return nil
+}
+
+// KmerCounts returns all distinct k-mers of the ESA with their number of occurrences
+// in lexicographic order, see EachKmer.
+func (e *Esa) KmerCounts(k int, canonical bool) ([]KmerCount, error) {
+	var counts []KmerCount
+	err := e.EachKmer(k, canonical, func(kmer []byte, count int) {
+		counts = append(counts, KmerCount{kmer, count})
+	})
+	return counts, err
+}
+
+// KmerSpectrum returns the k-mer spectrum of the ESA, that is the number of distinct k-mers
+// for every multiplicity: spectrum[m] is the number of k-mers that occur exactly m times.
+// See EachKmer for the k-mers that are counted.
+func (e *Esa) KmerSpectrum(k int, canonical bool) ([]int, error) {
+	var spectrum []int
+	err := e.EachKmer(k, canonical, func(kmer []byte, count int) {
+		for len(spectrum) <= count {
+			spectrum = append(spectrum, 0)
+		}
+		spectrum[count]++
+	})
+	return spectrum, err
+}
+
+// Return the k-mer at position p of the sequence, or false if it spans
+// a sentinel, a RecordSeparator or an 'N'.
+func (e *Esa) kmerAt(p, k int) ([]byte, bool) {
+	if p+k > len(e.s) {
+		return nil, false
+	}
+	for q := p; q < p+k; q++ {
+		c := e.s[q]
+		if e.isSentinel(q) || c == RecordSeparator || c == 'N' || c == 'n' {
+			return nil, false
+		}
+	}
+	return e.s[p : p+k], true
+}
+
+// Compare kmer to its reverse complement without computing it.
+// The result is 0 if both are equal, -1 if kmer is smaller and +1 otherwise.
+func compareRevComp(kmer []byte) int {
+	for i, j := 0, len(kmer)-1; i < len(kmer); i, j = i+1, j-1 {
+		c := dnaComplement[kmer[j]]
+		if kmer[i] != c {
+			if kmer[i] < c {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
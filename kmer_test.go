@@ -0,0 +1,94 @@
+package esaMatcher
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+func TestKmerCounts(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("AAGTAAGGNNAAGT"),
+		ranseq(2000, "ACGTN"),
+	}
+	for _, s := range seqs {
+		for _, k := range []int{1, 2, 3, 5} {
+			// Count all k-mers without N naively.
+			fwd := map[string]int{}
+			for p := 0; p+k <= len(s); p++ {
+				if w := s[p : p+k]; !containsN(w) {
+					fwd[string(w)]++
+				}
+			}
+			both := map[string]int{}
+			canonical := map[string]int{}
+			for w, c := range fwd {
+				rc := string(RevComp([]byte(w)))
+				both[w] += c
+				both[rc] += c
+				if rc < w {
+					canonical[rc] += c
+				} else {
+					canonical[w] += c
+				}
+			}
+
+			e := NewEsa(s, "")
+			checkKmers(t, &e, k, false, fwd)
+			if _, err := e.KmerCounts(k, true); err == nil {
+				t.Errorf("canonical k-mers of a single strand did not fail")
+			}
+			r := NewRevEsa(s, "")
+			checkKmers(t, &r, k, false, both)
+			checkKmers(t, &r, k, true, canonical)
+
+			spectrum, err := r.KmerSpectrum(k, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[int]int{}
+			for _, c := range canonical {
+				want[c]++
+			}
+			for m, c := range spectrum {
+				if c != want[m] {
+					t.Errorf("spectrum[%d] = %d, want %d", m, c, want[m])
+				}
+			}
+		}
+	}
+	e := NewEsa([]byte("ACGT"), "")
+	if _, err := e.KmerCounts(0, false); err == nil {
+		t.Errorf("KmerCounts(0) did not fail")
+	}
+}
+
+func checkKmers(t *testing.T, e *Esa, k int, canonical bool, want map[string]int) {
+	t.Helper()
+	counts, err := e.KmerCounts(k, canonical)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int{}
+	var kmers []string
+	for _, c := range counts {
+		got[string(c.Kmer)] = c.Count
+		kmers = append(kmers, string(c.Kmer))
+	}
+	if !sort.StringsAreSorted(kmers) {
+		t.Errorf("k-mers are not sorted: %v", kmers)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("KmerCounts(%d, %v) = %v, want %v", k, canonical, got, want)
+	}
+}
+
+func containsN(w []byte) bool {
+	for _, c := range w {
+		if c == 'N' {
+			return true
+		}
+	}
+	return false
+}
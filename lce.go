@@ -0,0 +1,51 @@
+package esaMatcher
+
+// WithLCE returns a copy of the ESA that answers LCE queries in O(log n) time.
+// It keeps the inverse suffix array and builds a range minimum structure over the lcp array,
+// which are the same structures used for suffix links, see WithSuffixLinks.
+// The returned ESA shares all other arrays with e.
+func (e *Esa) WithLCE() Esa {
+	return e.withNavigation()
+}
+
+// Isa returns the inverse suffix array of the ESA, that is the rank of every suffix,
+// or nil if the ESA was neither built WithLCE nor WithSuffixLinks.
+func (e *Esa) Isa() []int {
+	if e.nav == nil {
+		return nil
+	}
+	return e.nav.isa
+}
+
+// LCE returns the length of the longest common extension of the positions i and j
+// of the sequence, that is the length of the longest common prefix of the suffixes
+// starting at i and j. Extensions stop at the sentinels.
+// Both positions must be in [0, len(e.Sequence())).
+//
+// With WithLCE, the query takes O(log n) time, otherwise the suffixes are compared
+// character by character.
+//
+// Concept
+//
+// The lcp of two suffixes is the minimum of the lcp array between their ranks:
+// LCE(i,j) = min(lcp[k]) for ISA[i] < k <= ISA[j], if ISA[i] < ISA[j].
+func (e *Esa) LCE(i, j int) int {
+	if e.isSentinel(i) || e.isSentinel(j) {
+		return 0
+	}
+	if i == j {
+		return e.sentinelAfter(i) - i
+	}
+	if e.nav == nil {
+		l := 0
+		for !e.isSentinel(i+l) && !e.isSentinel(j+l) && e.s[i+l] == e.s[j+l] {
+			l++
+		}
+		return l
+	}
+	a, b := e.nav.isa[i], e.nav.isa[j]
+	if a > b {
+		a, b = b, a
+	}
+	return e.nav.lcp.min(a+1, b)
+}
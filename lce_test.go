@@ -0,0 +1,40 @@
+package esaMatcher
+
+import "testing"
+
+func TestLCE(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		[]byte("A$C#AC$"),
+		ranseq(300, "AC"),
+	}
+	for _, s := range seqs {
+		for _, e := range []Esa{NewEsa(s, ""), NewRevEsa(s, "")} {
+			le := e.WithLCE()
+			if e.Isa() != nil {
+				t.Errorf("Isa() = %v, want nil", e.Isa())
+			}
+			for i, p := range le.Sa() {
+				if le.Isa()[p] != i {
+					t.Fatalf("Isa()[%d] = %d, want %d", p, le.Isa()[p], i)
+				}
+			}
+			seq := e.Sequence()
+			for i := range seq {
+				for j := range seq {
+					want := 0
+					for !e.isSentinel(i+want) && !e.isSentinel(j+want) && seq[i+want] == seq[j+want] {
+						want++
+					}
+					if got := e.LCE(i, j); got != want {
+						t.Fatalf("LCE(%d, %d) = %d, want %d", i, j, got, want)
+					}
+					if got := le.LCE(i, j); got != want {
+						t.Fatalf("WithLCE: LCE(%d, %d) = %d, want %d", i, j, got, want)
+					}
+				}
+			}
+		}
+	}
+}
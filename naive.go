@@ -2,18 +2,22 @@ package esaMatcher
 
 import "sort"
 
+// symbol is the type of characters the ESA and its arrays can be computed on.
+type symbol interface {
+	~byte | ~uint16 | ~int32
+}
 
-type saSorter struct{
+type saSorter[T symbol] struct{
 	sa []int
-	str []byte
+	str []T
 	size int
 }
 
-func (m saSorter) Len() int{
+func (m saSorter[T]) Len() int{
 	return m.size
 }
 
-func (m saSorter) Less(i,j int) bool{
+func (m saSorter[T]) Less(i,j int) bool{
 	// return less(m.sa[i], m.sa[j],m.size, m.str)
 	istr := m.str[m.sa[i]:]
 	jstr := m.str[m.sa[j]:]
@@ -27,19 +31,20 @@ func (m saSorter) Less(i,j int) bool{
 	return len(istr) < len(jstr)
 }
 
-func (m saSorter) Swap(i,j int){
+func (m saSorter[T]) Swap(i,j int){
 	m.sa[i], m.sa[j] = m.sa[j], m.sa[i]
 }
 
-func saNaive(t []byte) []int {
+func saNaive[T symbol](t []T) []int {
 	n := len(t)
 	indeces := make([]int, n)
 
 	for idx := range t{
 		indeces[idx] = idx
 	}
-	sa := saSorter{indeces, t, n}
+	sa := saSorter[T]{indeces, t, n}
 	
 	sort.Sort(sa)
 	return sa.sa
 }
+
@@ -0,0 +1,7 @@
+//go:build !esadebug
+
+package esaMatcher
+
+// In debug builds, every constructed ESA is checked by Esa.Validate.
+// Build or test with -tags esadebug to enable.
+const debugValidate = false
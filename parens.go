@@ -0,0 +1,186 @@
+package esaMatcher
+
+import "math"
+
+// Number of parentheses per leaf of the tree of minimum excesses.
+const parenBlock = 256
+
+// Minimum excess after each bit of a byte of parentheses and the excess of the whole byte,
+// reading the bits from the least significant one.
+var parenByteMin, parenByteExcess [256]int
+
+func init() {
+	for v := range parenByteMin {
+		e, m := 0, math.MaxInt
+		for j := 0; j < 8; j++ {
+			if v>>j&1 == 1 {
+				e++
+			} else {
+				e--
+			}
+			m = minInt(m, e)
+		}
+		parenByteMin[v], parenByteExcess[v] = m, e
+	}
+}
+
+// A parentheses is a sequence of balanced parentheses, a set bit opens and an unset bit closes.
+// The excess of a position is the number of opening minus the number of closing parentheses
+// up to it. Besides the bits and their rank directory, it keeps the minimum excess of every
+// block of 256 parentheses in a minTree, 2 to 2.5 bits per parenthesis in total.
+type parentheses struct {
+	bits *bitVector
+	mins *minTree
+}
+
+// Build the parentheses from the first n bits of b.
+func newParentheses(b *bitVector, n int) *parentheses {
+	b.build()
+	mins := make([]int, (n+parenBlock-1)/parenBlock)
+	for i := range mins {
+		mins[i] = math.MaxInt
+	}
+	e := 0
+	for x := 0; x < n; x++ {
+		e += stepOf(b, x)
+		mins[x/parenBlock] = minInt(mins[x/parenBlock], e)
+	}
+	return &parentheses{b, newMinTree(mins)}
+}
+
+// Return the size of the parentheses in bytes.
+func (p *parentheses) size() int { return p.bits.size() + 8*len(p.mins.t) }
+
+// Return the change of the excess by the parenthesis at position x.
+func stepOf(b *bitVector, x int) int {
+	if b.get(x) {
+		return 1
+	}
+	return -1
+}
+
+// Return the excess at position x, which is 0 for x = -1.
+func (p *parentheses) excess(x int) int { return 2*p.bits.rank(x+1) - x - 1 }
+
+// Return the minimum excess of the positions a to b by scanning bits and bytes.
+func (p *parentheses) scanMin(a, b int) int {
+	e, m := p.excess(a-1), math.MaxInt
+	x := a
+	for ; x <= b && x%8 != 0; x++ {
+		e += stepOf(p.bits, x)
+		m = minInt(m, e)
+	}
+	for ; x+7 <= b; x += 8 {
+		v := byte(p.bits.words[x/64] >> (x % 64))
+		m = minInt(m, e+parenByteMin[v])
+		e += parenByteExcess[v]
+	}
+	for ; x <= b; x++ {
+		e += stepOf(p.bits, x)
+		m = minInt(m, e)
+	}
+	return m
+}
+
+// Return the last of the positions a to b with an excess of v, which must not be greater
+// than their minimum, by scanning bits and bytes backwards. Return -1 if there is none.
+func (p *parentheses) scanLast(a, b, v int) int {
+	e := p.excess(b)
+	x := b
+	for ; x >= a && (x+1)%8 != 0; x-- {
+		if e == v {
+			return x
+		}
+		e -= stepOf(p.bits, x)
+	}
+	for ; x-7 >= a; x -= 8 {
+		y := x - 7
+		c := byte(p.bits.words[y/64] >> (y % 64))
+		if before := e - parenByteExcess[c]; before+parenByteMin[c] > v {
+			e = before
+			continue
+		}
+		for ; ; x-- {
+			if e == v {
+				return x
+			}
+			e -= stepOf(p.bits, x)
+		}
+	}
+	for ; x >= a; x-- {
+		if e == v {
+			return x
+		}
+		e -= stepOf(p.bits, x)
+	}
+	return -1
+}
+
+// Return the minimum excess of the positions a to b.
+func (p *parentheses) minIn(a, b int) int {
+	ba, bb := a/parenBlock, b/parenBlock
+	if ba == bb {
+		return p.scanMin(a, b)
+	}
+	m := minInt(p.scanMin(a, (ba+1)*parenBlock-1), p.scanMin(bb*parenBlock, b))
+	if bb > ba+1 {
+		m = minInt(m, p.mins.min(ba+1, bb-1))
+	}
+	return m
+}
+
+// Return the last of the positions a to b whose excess is their minimum v.
+func (p *parentheses) lastIn(a, b, v int) int {
+	ba, bb := a/parenBlock, b/parenBlock
+	if ba == bb {
+		return p.scanLast(a, b, v)
+	}
+	if x := p.scanLast(bb*parenBlock, b, v); x >= 0 {
+		return x
+	}
+	if k := p.mins.prevLess(bb-1, v+1); k > ba {
+		return p.scanLast(k*parenBlock, (k+1)*parenBlock-1, v)
+	}
+	return p.scanLast(a, (ba+1)*parenBlock-1, v)
+}
+
+// Return the parentheses of the values v[0..n], where v[0] and v[n] are -1 and v[i] is
+// lcp(i) otherwise. Every value opens a parenthesis after it closed the parentheses of
+// all greater values before it that are still open.
+// The values of the open parentheses are kept on a stack, which holds up to n+1 integers
+// if the values increase, as for a text of a single repeated character.
+//
+// Concept
+//
+// Let i be the leftmost minimum of v[a..b] with a > 0. Every value in v[a..i-1] is greater
+// than v[i], hence the parenthesis of i is opened right after all parentheses of the
+// values in v[a..i-1] are closed. This is the last position before the opening parenthesis
+// of i where the excess is at its minimum from the one before the opening parenthesis of a
+// to the opening parenthesis of b. Later values in v[i+1..b] keep i open (Fischer and Heun, 2011).
+// See rmq.
+func lcpParentheses(n int, lcp func(i int) int) *parentheses {
+	b := newBitVector(2*n + 2)
+	var stack []int
+	x := 0
+	for i := 0; i <= n; i++ {
+		v := -1
+		if i > 0 && i < n {
+			v = lcp(i)
+		}
+		for len(stack) > 0 && stack[len(stack)-1] > v {
+			stack = stack[:len(stack)-1]
+			x++
+		}
+		b.set(x)
+		x++
+		stack = append(stack, v)
+	}
+	return newParentheses(b, 2*n+2)
+}
+
+// Return the index of the leftmost minimum of the values a to b of lcpParentheses with 0 < a ≤ b
+// in O(log n) time.
+func (p *parentheses) rmq(a, b int) int {
+	x, y := p.bits.select1(a)-1, p.bits.select1(b)
+	return p.bits.rank(p.lastIn(x, y, p.minIn(x, y)) + 1)
+}
@@ -0,0 +1,196 @@
+package esaMatcher
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// A QueryPool matches queries concurrently against a shared ESA.
+//
+// An Esa is not modified after its construction, hence all of its query methods
+// may be called from several goroutines at once. The QueryPool fans the queries out
+// over a fixed number of goroutines and returns the results in the order of the queries.
+type QueryPool struct {
+	esa     *Esa
+	workers int
+}
+
+// A QueryResult is the match of a single query of a stream.
+type QueryResult struct {
+	// Number of the query in the stream, starting at 0.
+	Index int
+	// The query itself.
+	Query []byte
+	// The longest match of the query as returned by Esa.GetMatch.
+	Match EsaInterval
+}
+
+// NewQueryPool returns a QueryPool on e with the given number of goroutines.
+// If workers is less than one, runtime.GOMAXPROCS(0) goroutines are used.
+func NewQueryPool(e *Esa, workers int) *QueryPool {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &QueryPool{e, workers}
+}
+
+// Return the number of goroutines of the pool.
+func (p *QueryPool) Workers() int { return p.workers }
+
+// MatchAll calls GetMatch for all queries and returns the matches in the order of the queries.
+// If ctx is cancelled before all queries are matched, the context's error is returned.
+func (p *QueryPool) MatchAll(ctx context.Context, queries [][]byte) ([]EsaInterval, error) {
+	matches := make([]EsaInterval, len(queries))
+	err := parallelFor(ctx, p.workers, len(queries), func(i int) {
+		matches[i] = p.esa.GetMatch(queries[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// MatchStream matches the queries received from the channel queries and sends the results
+// in the order of the queries on the returned channel. The returned channel is closed
+// when queries is closed and all results are sent, or when ctx is cancelled.
+func (p *QueryPool) MatchStream(ctx context.Context, queries <-chan []byte) <-chan QueryResult {
+	in := make(chan QueryResult)
+	go func() {
+		defer close(in)
+		for i := 0; ; i++ {
+			var q []byte
+			var ok bool
+			select {
+			case q, ok = <-queries:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case in <- QueryResult{Index: i, Query: q}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return orderedMap(ctx, p.workers, in, func(r QueryResult) QueryResult {
+		r.Match = p.esa.GetMatch(r.Query)
+		return r
+	})
+}
+
+// parallelFor calls f for all i in [0,n) using the given number of goroutines.
+// If ctx is cancelled, no further calls are started and the context's error is returned.
+func parallelFor(ctx context.Context, workers, n int, f func(i int)) error {
+	var next int64 = -1
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n {
+					return
+				}
+				f(i)
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// orderedMap applies f to all values received from in using the given number of
+// goroutines and sends the results on the returned channel in the order of the input.
+// At most 4*workers values are in flight at any time, hence the memory needed
+// for reordering is bounded. The returned channel is closed when in is closed and
+// all results are sent, or when ctx is cancelled.
+func orderedMap[In, Out any](ctx context.Context, workers int, in <-chan In, f func(In) Out) <-chan Out {
+	type job struct {
+		idx int
+		v   In
+	}
+	type result struct {
+		idx int
+		v   Out
+	}
+	window := make(chan struct{}, 4*workers)
+	jobs := make(chan job)
+	results := make(chan result)
+	out := make(chan Out)
+
+	// Dispatch the input in numbered jobs as long as the window has room.
+	go func() {
+		defer close(jobs)
+		for idx := 0; ; idx++ {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			var v In
+			var ok bool
+			select {
+			case v, ok = <-in:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- job{idx, v}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				select {
+				case results <- result{j.idx, f(j.v)}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Reorder the results and free their space in the window once they are sent.
+	go func() {
+		defer close(out)
+		pending := make(map[int]Out)
+		next := 0
+		for r := range results {
+			pending[r.idx] = r.v
+			for v, ok := pending[next]; ok; v, ok = pending[next] {
+				delete(pending, next)
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					// Drain the workers so they can finish.
+					for range results {
+					}
+					return
+				}
+				<-window
+				next++
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,83 @@
+package esaMatcher
+
+import (
+	"context"
+	"testing"
+)
+
+func TestQueryPool(t *testing.T) {
+	e := NewRevEsa(ranseq50KBP, "")
+	queries := make([][]byte, 2000)
+	for i := range queries {
+		queries[i] = ranseq(20+i%50, "ACGT")
+	}
+	want := make([]EsaInterval, len(queries))
+	for i, q := range queries {
+		want[i] = e.GetMatch(q)
+	}
+
+	for _, workers := range []int{0, 1, 7} {
+		p := NewQueryPool(&e, workers)
+		got, err := p.MatchAll(context.Background(), queries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("MatchAll with %d workers differs at query %d", workers, i)
+			}
+		}
+
+		in := make(chan []byte)
+		go func() {
+			for _, q := range queries {
+				in <- q
+			}
+			close(in)
+		}()
+		i := 0
+		for r := range p.MatchStream(context.Background(), in) {
+			if r.Index != i || r.Match != want[i] {
+				t.Fatalf("MatchStream with %d workers returned query %d at %d", workers, r.Index, i)
+			}
+			i++
+		}
+		if i != len(queries) {
+			t.Errorf("MatchStream returned %d results, want %d", i, len(queries))
+		}
+	}
+}
+
+func TestQueryPool_Cancel(t *testing.T) {
+	e := NewEsa(ranseq50KBP, "")
+	p := NewQueryPool(&e, 4)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.MatchAll(ctx, [][]byte{[]byte("ACGT")}); err == nil {
+		t.Error("MatchAll ignored the cancelled context")
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan []byte)
+	go func() {
+		for {
+			select {
+			case in <- []byte("ACGTACGT"):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	n := 0
+	for range p.MatchStream(ctx, in) {
+		n++
+		if n == 100 {
+			cancel()
+		}
+	}
+	if n < 100 {
+		t.Errorf("MatchStream stopped after %d results", n)
+	}
+}
@@ -0,0 +1,104 @@
+package esaMatcher
+
+import "fmt"
+
+// MaxPrefixK is the largest prefix length supported by Esa.WithPrefixTable.
+const MaxPrefixK = 14
+
+// A prefixTable stores the interval of every DNA word of length k in the ESA,
+// such that GetMatch can start at depth k instead of the root.
+type prefixTable struct {
+	k int
+	// Start and end of the interval of each word, indexed by the word's code.
+	// Words that do not occur have start -1.
+	bounds []int
+}
+
+// WithPrefixTable returns a copy of the ESA that looks up the first k characters of
+// queries in a table of 4^k intervals, as done in phylonium.
+// GetMatch consults the table automatically for every query that starts with k characters
+// of 'A', 'C', 'G' or 'T', which saves the first k calls of GetInterval where most of the
+// branching happens. Other queries are matched from the root as before.
+//
+// The parameter k trades memory for speed: the table takes 16*4^k bytes,
+// for example 16 MiB for k=10 and 4 GiB for k=14. k must be in [1, MaxPrefixK].
+// The returned ESA shares all arrays with e.
+func (e *Esa) WithPrefixTable(k int) (Esa, error) {
+	if k < 1 || k > MaxPrefixK {
+		return Esa{}, fmt.Errorf("prefix length %d is not in [1,%d]", k, MaxPrefixK)
+	}
+	t := &prefixTable{k, make([]int, 2<<(2*k))}
+	for i := range t.bounds {
+		t.bounds[i] = -1
+	}
+	e.fillPrefixTable(t, e.Root(), 0, 0)
+	c := *e
+	c.prefix = t
+	return c, nil
+}
+
+// Return the length of the words in the prefix table, or 0 if there is none.
+func (e *Esa) PrefixK() int {
+	if e.prefix == nil {
+		return 0
+	}
+	return e.prefix.k
+}
+
+// Fill the table with the intervals of all words that extend the first depth characters
+// of the interval iv, whose code is code.
+func (e *Esa) fillPrefixTable(t *prefixTable, iv EsaInterval, depth, code int) {
+	if depth == t.k {
+		t.bounds[2*code] = iv.start
+		t.bounds[2*code+1] = iv.end
+		return
+	}
+	for r, c := range []byte("ACGT") {
+		if child := e.extend(iv, depth, c); child.start != -1 {
+			e.fillPrefixTable(t, child, depth+1, code<<2|r)
+		}
+	}
+}
+
+// Return the interval of the suffixes in iv, which share their first depth characters,
+// that continue with c.
+func (e *Esa) extend(iv EsaInterval, depth int, c byte) EsaInterval {
+	if iv.start != iv.end && depth >= iv.l {
+		return e.GetInterval(iv, c)
+	}
+	p := e.sa[iv.start] + depth
+	if e.isSentinel(p) || e.s[p] != c {
+		return EmptyEsaInterval()
+	}
+	return iv
+}
+
+// Look up the first k characters of query in the table. If found, the interval of the word
+// is returned, otherwise ok is false.
+func (t *prefixTable) lookup(e *Esa, query []byte) (iv EsaInterval, ok bool) {
+	if len(query) < t.k {
+		return iv, false
+	}
+	code := 0
+	for _, c := range query[:t.k] {
+		r := dnaCode[c]
+		if r < 0 {
+			return iv, false
+		}
+		code = code<<2 | int(r)
+	}
+	start := t.bounds[2*code]
+	if start == -1 {
+		return iv, false
+	}
+	return e.Interval(start, t.bounds[2*code+1]), true
+}
+
+// The two bit code of the nucleotides, -1 for all other characters.
+var dnaCode = func() (code [256]int8) {
+	for i := range code {
+		code[i] = -1
+	}
+	code['A'], code['C'], code['G'], code['T'] = 0, 1, 2, 3
+	return code
+}()
@@ -0,0 +1,49 @@
+package esaMatcher
+
+import "testing"
+
+func TestWithPrefixTable(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		[]byte("ACGTNACGTA$CGTTTGCA"),
+		ranseq50KBP,
+	}
+	for _, seq := range seqs {
+		for _, e := range []Esa{NewEsa(seq, ""), NewRevEsa(seq, "")} {
+			for _, k := range []int{1, 3, 6} {
+				pe, err := e.WithPrefixTable(k)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if pe.PrefixK() != k || e.PrefixK() != 0 {
+					t.Errorf("PrefixK is %d and %d", pe.PrefixK(), e.PrefixK())
+				}
+				queries := [][]byte{[]byte("ACGTNACGT"), []byte("ACAT"), []byte("A"), []byte("NACG")}
+				for i := 0; i+30 < len(seq) && i < 2000; i += 7 {
+					queries = append(queries, seq[i:i+30], append(append([]byte{}, seq[i:i+k+2]...), 'N'))
+				}
+				for i := 0; i < 200; i++ {
+					queries = append(queries, ranseq(k+i%10, "ACGT"))
+				}
+				for _, q := range queries {
+					if got, want := pe.GetMatch(q), e.GetMatch(q); got != want {
+						t.Fatalf("GetMatch(%q) with k=%d is %v, want %v", q, k, got, want)
+					}
+				}
+			}
+		}
+	}
+	e := NewEsa([]byte("ACGT"), "")
+	for _, k := range []int{0, MaxPrefixK + 1} {
+		if _, err := e.WithPrefixTable(k); err == nil {
+			t.Errorf("WithPrefixTable accepted k=%d", k)
+		}
+	}
+}
+
+func Benchmark_GetMatch_Prefix10_5MBP(b *testing.B) {
+	e := NewEsa(ranseq5MBP, "")
+	pe, _ := e.WithPrefixTable(10)
+	benchmarkGetMatch(b, pe)
+}
@@ -0,0 +1,216 @@
+package esaMatcher
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"sort"
+)
+
+// SearchRegexp returns all positions in the sequence of the ESA where a match of the
+// regular expression expr starts. The positions are sorted in ascending order.
+//
+// The expression uses the RE2 syntax of the regexp package, for example
+// "GAATTC|GGATCC" or "TATA[AT]A[AT]". Only a restricted subset is supported:
+// Anchors and word boundaries are rejected and the expression must not match the empty string.
+// Matches never extend over the sentinels '$' and '#' separating the strands.
+// Note that unbounded repetitions like ".*" have to follow each branch of the tree to its end
+// and therefore can be slow.
+//
+// Implementation
+//
+// The expression is compiled into an automaton which is walked over the lcp-intervals
+// of the ESA, starting at the root. Each interval is extended by the characters of
+// its edge until the automaton either accepts, in which case every suffix of the
+// interval is a match, or has no active state left, in which case the whole subtree
+// is skipped. Otherwise the walk continues with the child intervals.
+// Hence, only branches that can still lead to a match are explored.
+func (e *Esa) SearchRegexp(expr string) ([]int, error) {
+	re, err := compileEsaRegexp(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	type frame struct {
+		i      EsaInterval
+		depth  int
+		states []uint32
+	}
+
+	var pos []int
+	n := len(e.s)
+	// The states along an edge alternate between two buffers.
+	buf := [2][]uint32{make([]uint32, 0, len(re.prog.Inst)), make([]uint32, 0, len(re.prog.Inst))}
+	stack := []frame{{e.Root(), 0, re.start()}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// Length of the edge label up to which all suffixes of the interval are equal.
+		l := f.i.l
+		if f.i.start == f.i.end {
+			l = n - e.sa[f.i.start]
+		}
+		states := f.states
+		matched := false
+		p := e.sa[f.i.start]
+		for k := f.depth; k < l && len(states) > 0; k++ {
+			if e.isSentinel(p + k) {
+				states = nil
+				break
+			}
+			states, matched = re.step(buf[k%2][:0], states, e.s[p+k])
+			if matched {
+				pos = append(pos, e.sa[f.i.start:f.i.end+1]...)
+				break
+			}
+		}
+		if matched || len(states) == 0 || f.i.start == f.i.end {
+			continue
+		}
+		// The children share a copy of the states, since the buffers are reused.
+		states = append([]uint32(nil), states...)
+		e.eachChild(f.i, func(c EsaInterval) {
+			stack = append(stack, frame{c, l, states})
+		})
+	}
+	sort.Ints(pos)
+	return pos, nil
+}
+
+// isSentinel reports whether position p of the sequence holds one of the sentinels
+// that terminate the strands, that is '#' between the strands or the final '$',
+// or a RecordSeparator of an ESA built by NewFastaEsa.
+func (e *Esa) isSentinel(p int) bool {
+	return p == len(e.s)-1 || p == e.strandSize || (e.separators != nil && e.s[p] == RecordSeparator)
+}
+
+// sentinelAfter returns the position of the first sentinel at or after position p.
+func (e *Esa) sentinelAfter(p int) int {
+	end := len(e.s) - 1
+	if p <= e.strandSize {
+		end = e.strandSize
+	}
+	if k := sort.SearchInts(e.separators, p); k < len(e.separators) && e.separators[k] < end {
+		return e.separators[k]
+	}
+	return end
+}
+
+// esaRegexp is an automaton compiled from a regular expression that is simulated
+// on sets of active states, one byte at a time. Each byte of the text is matched
+// as the character with the same code point.
+type esaRegexp struct {
+	prog *syntax.Prog
+	// The bytes read by each state.
+	bytes   []byteSet
+	seen    []bool
+	visited []uint32
+}
+
+// A byteSet holds one bit for every byte value.
+type byteSet [4]uint64
+
+func (s *byteSet) add(c byte) {
+	s[c>>6] |= 1 << (c & 63)
+}
+
+func (s *byteSet) has(c byte) bool {
+	return s[c>>6]&(1<<(c&63)) != 0
+}
+
+// Compile expr to an automaton and reject the parts of the syntax that are not supported.
+func compileEsaRegexp(expr string) (*esaRegexp, error) {
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, err
+	}
+	for _, inst := range prog.Inst {
+		if inst.Op == syntax.InstEmptyWidth {
+			return nil, fmt.Errorf("regexp %q: anchors and word boundaries are not supported", expr)
+		}
+	}
+	re := &esaRegexp{prog, make([]byteSet, len(prog.Inst)), make([]bool, len(prog.Inst)), nil}
+	for pc := range prog.Inst {
+		inst := &prog.Inst[pc]
+		for c := 0; c < 256; c++ {
+			ok := false
+			switch inst.Op {
+			case syntax.InstRune1, syntax.InstRune:
+				ok = inst.MatchRune(rune(c))
+			case syntax.InstRuneAny:
+				ok = true
+			case syntax.InstRuneAnyNotNL:
+				ok = c != '\n'
+			}
+			if ok {
+				re.bytes[pc].add(byte(c))
+			}
+		}
+	}
+	if re.matches(re.start()) {
+		return nil, fmt.Errorf("regexp %q matches the empty string", expr)
+	}
+	return re, nil
+}
+
+// Return the set of states that are active before reading any character.
+func (re *esaRegexp) start() []uint32 {
+	states := re.closure(nil, uint32(re.prog.Start))
+	re.reset()
+	return states
+}
+
+// Append the states that are active after reading c in states to next and
+// report whether one of them accepts. next must not share memory with states.
+func (re *esaRegexp) step(next, states []uint32, c byte) ([]uint32, bool) {
+	for _, pc := range states {
+		if re.bytes[pc].has(c) {
+			next = re.closure(next, re.prog.Inst[pc].Out)
+		}
+	}
+	re.reset()
+	return next, re.matches(next)
+}
+
+// Add pc and all states reachable from it without reading a character to states.
+func (re *esaRegexp) closure(states []uint32, pc uint32) []uint32 {
+	if re.seen[pc] {
+		return states
+	}
+	re.seen[pc] = true
+	re.visited = append(re.visited, pc)
+	inst := &re.prog.Inst[pc]
+	switch inst.Op {
+	case syntax.InstAlt, syntax.InstAltMatch:
+		states = re.closure(states, inst.Out)
+		states = re.closure(states, inst.Arg)
+	case syntax.InstCapture, syntax.InstNop:
+		states = re.closure(states, inst.Out)
+	case syntax.InstFail:
+	default:
+		states = append(states, pc)
+	}
+	return states
+}
+
+// Clear the marks set by closure.
+func (re *esaRegexp) reset() {
+	for _, pc := range re.visited {
+		re.seen[pc] = false
+	}
+	re.visited = re.visited[:0]
+}
+
+// Report whether any state in states accepts.
+func (re *esaRegexp) matches(states []uint32) bool {
+	for _, pc := range states {
+		if re.prog.Inst[pc].Op == syntax.InstMatch {
+			return true
+		}
+	}
+	return false
+}
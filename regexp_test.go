@@ -0,0 +1,104 @@
+package esaMatcher
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestSearchRegexp(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("ACTTCACAAA"), //ranseq
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		[]byte("TCTAATGAATATGTAGGATACGAATCGGATGATTCTCGAGCATCAGCATGGA" +
+			"GGACTCCATGTGTTAAGTCACCGACTGCGTGCCACCTGCGTCTTCGAAACGG"),
+		ranseq50KBP,
+	}
+	exprs := []string{
+		"GAATTC|GGATCC",
+		"TATA[AT]A[AT]",
+		"A",
+		"CA+T",
+		"AC?A",
+		"G.A",
+		"(?i)gat",
+		"[^A]AA",
+		"AA.*TT",
+		"ACGTACGTACGTACGTACGT",
+	}
+	for _, seq := range seqs {
+		for _, rev := range []bool{false, true} {
+			var e Esa
+			if rev {
+				e = NewRevEsa(seq, "")
+			} else {
+				e = NewEsa(seq, "")
+			}
+			for _, expr := range exprs {
+				got, err := e.SearchRegexp(expr)
+				if err != nil {
+					t.Fatalf("SearchRegexp(%q): %v", expr, err)
+				}
+				want := scanRegexp(&e, expr)
+				if len(got) != len(want) {
+					t.Errorf("SearchRegexp(%q) found %d matches, want %d", expr, len(got), len(want))
+					continue
+				}
+				for i := range got {
+					if got[i] != want[i] {
+						t.Errorf("SearchRegexp(%q) match %d is at %d, want %d", expr, i, got[i], want[i])
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSearchRegexp_Invalid(t *testing.T) {
+	e := NewEsa([]byte("ACAAACATAT"), "")
+	for _, expr := range []string{"A(", "^ACA", "A*", "A|"} {
+		if _, err := e.SearchRegexp(expr); err == nil {
+			t.Errorf("SearchRegexp(%q) did not return an error", expr)
+		}
+	}
+}
+
+func TestEsaRegexp_Step(t *testing.T) {
+	re, err := compileEsaRegexp("(?i)g[AT]+")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf [2][]uint32
+	for i := range buf {
+		buf[i] = make([]uint32, 0, len(re.prog.Inst))
+	}
+	start := re.start()
+	allocs := testing.AllocsPerRun(100, func() {
+		states, _ := re.step(buf[0][:0], start, 'g')
+		states, _ = re.step(buf[1][:0], states, 'T')
+		if _, matched := re.step(buf[0][:0], states, 'a'); !matched {
+			t.Error("gTa was not matched")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("step allocates %v times, want 0", allocs)
+	}
+}
+
+// Find the starts of all matches of expr by a linear scan of each strand.
+func scanRegexp(e *Esa, expr string) []int {
+	re := regexp.MustCompile("^(?:" + expr + ")")
+	s := e.Sequence()
+	var pos []int
+	for i := range s {
+		end := len(s) - 1
+		if i <= e.StrandSize() {
+			end = e.StrandSize()
+		}
+		if re.Match(s[i:end]) {
+			pos = append(pos, i)
+		}
+	}
+	return pos
+}
@@ -0,0 +1,98 @@
+package esaMatcher
+
+import "math"
+
+// A minTree is a segment tree over an array of integers. It answers range minimum
+// queries and finds the nearest positions left and right of an index that hold
+// a value smaller than a threshold, each in O(log n) time. It takes 2n to 4n integers.
+type minTree struct {
+	n    int
+	size int
+	t    []int
+}
+
+// Build the minTree over a.
+func newMinTree(a []int) *minTree {
+	size := 1
+	for size < len(a) {
+		size <<= 1
+	}
+	t := make([]int, 2*size)
+	copy(t[size:], a)
+	for i := size + len(a); i < 2*size; i++ {
+		t[i] = math.MaxInt
+	}
+	for i := size - 1; i > 0; i-- {
+		t[i] = minInt(t[2*i], t[2*i+1])
+	}
+	return &minTree{len(a), size, t}
+}
+
+// Return the minimum of a[i..j], both included.
+func (m *minTree) min(i, j int) int {
+	res := math.MaxInt
+	for i, j = i+m.size, j+m.size+1; i < j; i, j = i>>1, j>>1 {
+		if i&1 == 1 {
+			res = minInt(res, m.t[i])
+			i++
+		}
+		if j&1 == 1 {
+			j--
+			res = minInt(res, m.t[j])
+		}
+	}
+	return res
+}
+
+// Return the largest x <= k with a[x] < v, or -1 if there is none.
+func (m *minTree) prevLess(k, v int) int {
+	i := k + m.size
+	if m.t[i] < v {
+		return k
+	}
+	for ; i > 1; i >>= 1 {
+		// The left sibling covers the positions directly left of the subtree of i.
+		if i&1 == 1 && m.t[i-1] < v {
+			i--
+			for i < m.size {
+				if m.t[2*i+1] < v {
+					i = 2*i + 1
+				} else {
+					i = 2 * i
+				}
+			}
+			return i - m.size
+		}
+	}
+	return -1
+}
+
+// Return the smallest x >= k with a[x] < v, or n if there is none.
+func (m *minTree) nextLess(k, v int) int {
+	i := k + m.size
+	if m.t[i] < v {
+		return k
+	}
+	for ; i > 1; i >>= 1 {
+		// The right sibling covers the positions directly right of the subtree of i.
+		if i&1 == 0 && m.t[i+1] < v {
+			i++
+			for i < m.size {
+				if m.t[2*i] < v {
+					i = 2 * i
+				} else {
+					i = 2*i + 1
+				}
+			}
+			return i - m.size
+		}
+	}
+	return m.n
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,298 @@
+package esaMatcher
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The file format of a serialized ESA starts with these bytes followed by its version.
+const (
+	esaMagic   = "ESAM"
+	esaVersion = 1
+)
+
+// Flags of the serialized ESA.
+const (
+	// The ESA was built by NewFastaEsa and its record separators are sentinels.
+	esaFlagSeparators = 1 << iota
+)
+
+// WriteTo writes the ESA to w in a binary format that is read by ReadEsa.
+// The sequence, the suffix array and the lcp array are written, the child table is
+// computed again when the ESA is read. Prefix tables and the structures for
+// suffix links and LCE queries are not written.
+// WriteTo returns the number of bytes written.
+//
+// All integers are written in little endian byte order. The arrays use 4 bytes per
+// entry if their values fit, otherwise 8 bytes.
+func (e *Esa) WriteTo(w io.Writer) (int64, error) {
+	bw := &binWriter{w: bufio.NewWriter(w)}
+	bw.bytes([]byte(esaMagic))
+	bw.uint(esaVersion)
+	bw.uint(uint64(e.strandSize))
+	var flags uint64
+	if e.separators != nil {
+		flags |= esaFlagSeparators
+	}
+	bw.uint(flags)
+	bw.bytes([]byte(e.alphabet.name))
+	bw.bytes(e.alphabet.symbols)
+	bw.bytes(e.s)
+	bw.ints(e.sa)
+	bw.ints(e.lcp)
+	if bw.err == nil {
+		bw.err = bw.w.Flush()
+	}
+	return bw.n, bw.err
+}
+
+// ReadEsa reads an ESA written by Esa.WriteTo. The ESA is checked with
+// Validate(ValidateFast) to detect corrupted input.
+//
+// ReadEsa does not read beyond the end of the ESA, hence other data, like a RecordMap,
+// may follow on the same stream. If r is not an io.ByteReader, the small header fields are read
+// one byte at a time, so r should be buffered, for example by a *bufio.Reader.
+func ReadEsa(r io.Reader) (Esa, error) {
+	br := newBinReader(r)
+	magic := br.bytes()
+	if br.err == nil && string(magic) != esaMagic {
+		return Esa{}, errors.New("input is not a serialized ESA")
+	}
+	if v := br.uint(); br.err == nil && v != esaVersion {
+		return Esa{}, fmt.Errorf("unsupported ESA format version %d", v)
+	}
+	var e Esa
+	e.strandSize = int(br.uint())
+	flags := br.uint()
+	name, symbols := br.bytes(), br.bytes()
+	e.s = br.bytes()
+	e.sa = br.ints()
+	e.lcp = br.ints()
+	if br.err != nil {
+		return Esa{}, fmt.Errorf("reading ESA: %w", br.err)
+	}
+	a, err := lookupAlphabet(string(name), string(symbols))
+	if err != nil {
+		return Esa{}, err
+	}
+	e.alphabet = a
+	if len(e.lcp) != len(e.sa)+1 || len(e.s) != len(e.sa) {
+		return Esa{}, errors.New("reading ESA: arrays differ in length")
+	}
+	if flags&esaFlagSeparators != 0 {
+		e.setSeparators()
+	} else {
+		e.cld = Cld(e.lcp)
+	}
+	if err := e.Validate(ValidateFast); err != nil {
+		return Esa{}, fmt.Errorf("reading ESA: %w", err)
+	}
+	return e, nil
+}
+
+// WriteTo writes the record map to w in a binary format that is read by ReadRecordMap.
+func (m *RecordMap) WriteTo(w io.Writer) (int64, error) {
+	bw := &binWriter{w: bufio.NewWriter(w)}
+	bw.uint(uint64(m.strandSize))
+	bw.uint(uint64(len(m.names)))
+	for _, name := range m.names {
+		bw.bytes([]byte(name))
+	}
+	bw.ints(m.starts)
+	if bw.err == nil {
+		bw.err = bw.w.Flush()
+	}
+	return bw.n, bw.err
+}
+
+// ReadRecordMap reads a record map written by RecordMap.WriteTo.
+// Like ReadEsa, it does not read beyond the end of the record map.
+func ReadRecordMap(r io.Reader) (*RecordMap, error) {
+	br := newBinReader(r)
+	m := &RecordMap{}
+	m.strandSize = int(br.uint())
+	n := br.uint()
+	for i := uint64(0); i < n && br.err == nil; i++ {
+		m.names = append(m.names, string(br.bytes()))
+	}
+	m.starts = br.ints()
+	if br.err != nil {
+		return nil, fmt.Errorf("reading record map: %w", br.err)
+	}
+	if len(m.starts) != len(m.names) {
+		return nil, errors.New("reading record map: names and starts differ in length")
+	}
+	return m, nil
+}
+
+// WriteIndex writes e followed by its record map m to w, which is the index file
+// written by the esa build command. It is read by ReadIndex.
+func WriteIndex(w io.Writer, e *Esa, m *RecordMap) (int64, error) {
+	n, err := e.WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	k, err := m.WriteTo(w)
+	return n + k, err
+}
+
+// ReadIndex reads an ESA and its record map written by WriteIndex.
+// Like ReadEsa, it does not read beyond the end of the index and r should be buffered.
+func ReadIndex(r io.Reader) (Esa, *RecordMap, error) {
+	e, err := ReadEsa(r)
+	if err != nil {
+		return Esa{}, nil, err
+	}
+	m, err := ReadRecordMap(r)
+	if err != nil {
+		return Esa{}, nil, err
+	}
+	if m.strandSize != e.strandSize {
+		return Esa{}, nil, errors.New("reading index: the record map does not belong to the ESA")
+	}
+	return e, m, nil
+}
+
+// Return the predefined alphabet with the given name and symbols or a new one.
+func lookupAlphabet(name, symbols string) (*Alphabet, error) {
+	for _, a := range []*Alphabet{DNA, IUPAC, RNA, Protein, Bytes} {
+		if a.name == name && string(a.symbols) == symbols {
+			return a, nil
+		}
+	}
+	return NewAlphabet(name, symbols)
+}
+
+// A binWriter writes the parts of the binary format and keeps the first error.
+type binWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (b *binWriter) write(p []byte) {
+	if b.err != nil {
+		return
+	}
+	n, err := b.w.Write(p)
+	b.n += int64(n)
+	b.err = err
+}
+
+func (b *binWriter) uint(v uint64) {
+	b.write(b.buf[:binary.PutUvarint(b.buf[:], v)])
+}
+
+func (b *binWriter) bytes(p []byte) {
+	b.uint(uint64(len(p)))
+	b.write(p)
+}
+
+// Write the length of a, its width in bytes and its values.
+func (b *binWriter) ints(a []int) {
+	width := 4
+	for _, v := range a {
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			width = 8
+			break
+		}
+	}
+	b.uint(uint64(len(a)))
+	b.uint(uint64(width))
+	for _, v := range a {
+		if width == 4 {
+			binary.LittleEndian.PutUint32(b.buf[:], uint32(int32(v)))
+		} else {
+			binary.LittleEndian.PutUint64(b.buf[:], uint64(v))
+		}
+		b.write(b.buf[:width])
+	}
+}
+
+// A binReader reads the parts of the binary format and keeps the first error.
+// It never reads more bytes from r than the parts it is asked for.
+type binReader struct {
+	r   io.Reader
+	br  io.ByteReader
+	err error
+}
+
+func newBinReader(r io.Reader) *binReader {
+	b := &binReader{r: r}
+	if br, ok := r.(io.ByteReader); ok {
+		b.br = br
+	} else {
+		b.br = &oneByteReader{r: r}
+	}
+	return b
+}
+
+// A oneByteReader reads single bytes from an unbuffered reader.
+type oneByteReader struct {
+	r   io.Reader
+	buf [1]byte
+}
+
+func (o *oneByteReader) ReadByte() (byte, error) {
+	_, err := io.ReadFull(o.r, o.buf[:])
+	return o.buf[0], err
+}
+
+func (b *binReader) uint() uint64 {
+	if b.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(b.br)
+	b.err = err
+	return v
+}
+
+// Read n bytes. The data is read in chunks so that a corrupted length
+// does not allocate more memory than the input holds.
+func (b *binReader) read(n uint64) []byte {
+	var buf bytes.Buffer
+	if b.err == nil {
+		_, b.err = io.CopyN(&buf, b.r, int64(n))
+		if b.err == io.EOF {
+			b.err = io.ErrUnexpectedEOF
+		}
+	}
+	return buf.Bytes()
+}
+
+func (b *binReader) bytes() []byte {
+	return b.read(b.uint())
+}
+
+func (b *binReader) ints() []int {
+	n := b.uint()
+	width := b.uint()
+	if b.err == nil && width != 4 && width != 8 {
+		b.err = fmt.Errorf("invalid integer width %d", width)
+	}
+	if b.err != nil || n > math.MaxInt64/8 {
+		if b.err == nil {
+			b.err = errors.New("invalid array length")
+		}
+		return nil
+	}
+	p := b.read(n * width)
+	if b.err != nil {
+		return nil
+	}
+	a := make([]int, n)
+	for i := range a {
+		if width == 4 {
+			a[i] = int(int32(binary.LittleEndian.Uint32(p[4*i:])))
+		} else {
+			a[i] = int(int64(binary.LittleEndian.Uint64(p[8*i:])))
+		}
+	}
+	return a
+}
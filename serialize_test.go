@@ -0,0 +1,148 @@
+package esaMatcher
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteReadEsa(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		ranseq(1000, "ACGT"),
+	}
+	var esas []Esa
+	for _, s := range seqs {
+		esas = append(esas, NewEsa(s, ""), NewRevEsa(s, ""))
+	}
+	b, err := NewAlphabetEsa([]byte("A$C#AC\x00\xff"), Bytes, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	esas = append(esas, b)
+	for _, e := range esas {
+		var buf bytes.Buffer
+		n, err := e.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(buf.Len()) {
+			t.Errorf("WriteTo returned %d bytes, wrote %d", n, buf.Len())
+		}
+		r, err := ReadEsa(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range [][2]interface{}{
+			{r.Sequence(), e.Sequence()}, {r.Sa(), e.Sa()}, {r.Lcp(), e.Lcp()},
+			{r.Cld(), e.Cld()}, {r.StrandSize(), e.StrandSize()}, {r.Alphabet(), e.Alphabet()},
+		} {
+			if fmt.Sprint(a[0]) != fmt.Sprint(a[1]) {
+				t.Errorf("read %v, want %v", a[0], a[1])
+			}
+		}
+	}
+}
+
+func TestReadEsa_Corrupt(t *testing.T) {
+	e := NewEsa([]byte("ACAAACATAT"), "")
+	var buf bytes.Buffer
+	e.WriteTo(&buf)
+	data := buf.Bytes()
+	for _, c := range []struct {
+		name string
+		data []byte
+		// Part of the expected error message, if any.
+		want string
+	}{
+		{"empty", nil, ""},
+		// The magic bytes follow their length.
+		{"magic", append(append([]byte{data[0]}, "XSAM"...), data[5:]...), "not a serialized ESA"},
+		{"version", append(append([]byte{}, data[:5]...), append([]byte{9}, data[6:]...)...), "version 9"},
+		{"truncated", data[:len(data)-3], ""},
+		{"sa", func() []byte {
+			d := append([]byte{}, data...)
+			d[len(d)-4*len(e.Lcp())-5]++
+			return d
+		}(), ""},
+	} {
+		_, err := ReadEsa(bytes.NewReader(c.data))
+		if err == nil {
+			t.Errorf("%s: ReadEsa did not fail", c.name)
+		} else if !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: ReadEsa failed with %q, want %q", c.name, err, c.want)
+		}
+	}
+}
+
+// An unbuffered reader that does not implement io.ByteReader.
+type plainReader struct{ r io.Reader }
+
+func (p plainReader) Read(b []byte) (int, error) { return p.r.Read(b) }
+
+func TestReadEsa_Stream(t *testing.T) {
+	records := []FastaRecord{{"a", []byte("ACGT")}, {"b", []byte("GG")}}
+	e, m := NewFastaEsa(records, false, "")
+	var buf bytes.Buffer
+	e.WriteTo(&buf)
+	m.WriteTo(&buf)
+	buf.WriteString("tail")
+	r := plainReader{&buf}
+	if _, err := ReadEsa(r); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadRecordMap(r); err != nil {
+		t.Fatalf("ReadRecordMap after ReadEsa: %v", err)
+	}
+	if rest, _ := io.ReadAll(r); string(rest) != "tail" {
+		t.Errorf("ReadEsa and ReadRecordMap left %q, want %q", rest, "tail")
+	}
+}
+
+func TestWriteReadRecordMap(t *testing.T) {
+	records := []FastaRecord{{"a", []byte("ACGT")}, {"b", []byte("GG")}, {"c", []byte("TTA")}}
+	_, m := NewFastaEsa(records, true, "")
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	r, err := ReadRecordMap(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(*r) != fmt.Sprint(*m) {
+		t.Errorf("read %v, want %v", *r, *m)
+	}
+}
+
+func TestWriteReadIndex(t *testing.T) {
+	records := []FastaRecord{{"a", []byte("ACGT")}, {"b", []byte("GG")}}
+	e, m := NewFastaEsa(records, true, "")
+	var buf bytes.Buffer
+	if _, err := WriteIndex(&buf, &e, m); err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteString("tail")
+	r := plainReader{&buf}
+	read, rm, err := ReadIndex(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(read.Sequence()) != string(e.Sequence()) || fmt.Sprint(*rm) != fmt.Sprint(*m) {
+		t.Errorf("ReadIndex = %q, %v, want %q, %v", read.Sequence(), *rm, e.Sequence(), *m)
+	}
+	if rest, _ := io.ReadAll(r); string(rest) != "tail" {
+		t.Errorf("ReadIndex left %q, want %q", rest, "tail")
+	}
+
+	// The record map of another ESA is rejected.
+	_, other := NewFastaEsa(records[:1], true, "")
+	buf.Reset()
+	e.WriteTo(&buf)
+	other.WriteTo(&buf)
+	if _, _, err := ReadIndex(&buf); err == nil {
+		t.Error("ReadIndex accepted the record map of another ESA")
+	}
+}
@@ -0,0 +1,72 @@
+package esaMatcher
+
+// EsaStats summarizes an ESA, see Esa.Stats.
+type EsaStats struct {
+	// Length of the text including the sentinels.
+	Length int
+	// Length of a single strand, see Esa.StrandSize.
+	StrandSize int
+	// Name of the alphabet of the text.
+	Alphabet string
+	// Number of occurrences of each byte of the text, not counting the sentinels.
+	Composition map[byte]int
+	// Largest and mean value of the lcp array, not counting the entries at both ends.
+	MaxLcp  int
+	MeanLcp float64
+	// Number of lcp-intervals including the root, that is the number of inner
+	// nodes of the corresponding suffix tree.
+	Intervals int
+	// Number of distinct non-empty substrings of the text that do not contain a sentinel.
+	DistinctSubstrings int
+}
+
+// Stats returns the summary statistics of the ESA in O(n) time.
+//
+// Each suffix contributes the prefixes that it does not share with its predecessor
+// in the suffix array to the distinct substrings, that is its length up to the
+// next sentinel minus its lcp value.
+func (e *Esa) Stats() EsaStats {
+	st := EsaStats{
+		Length:      len(e.s),
+		StrandSize:  e.strandSize,
+		Composition: map[byte]int{},
+	}
+	if e.alphabet != nil {
+		st.Alphabet = e.alphabet.name
+	}
+	var counts [256]int
+	for p, c := range e.s {
+		if !e.isSentinel(p) {
+			counts[c]++
+		}
+	}
+	for c, n := range counts {
+		if n > 0 {
+			st.Composition[byte(c)] = n
+		}
+	}
+	sum := 0
+	for i, p := range e.sa {
+		l := e.lcp[i]
+		if i == 0 {
+			l = 0
+		} else {
+			sum += l
+			if l > st.MaxLcp {
+				st.MaxLcp = l
+			}
+		}
+		st.DistinctSubstrings += e.sentinelAfter(p) - p - l
+	}
+	if len(e.sa) > 1 {
+		st.MeanLcp = float64(sum) / float64(len(e.sa)-1)
+	}
+	st.Intervals = BottomUp(e, func(iv EsaInterval, children []int) int {
+		n := 1
+		for _, c := range children {
+			n += c
+		}
+		return n
+	})
+	return st
+}
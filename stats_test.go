@@ -0,0 +1,64 @@
+package esaMatcher
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		ranseq(300, "ACGT"),
+	}
+	for _, s := range seqs {
+		for _, e := range []Esa{NewEsa(s, ""), NewRevEsa(s, "")} {
+			st := e.Stats()
+			seq := e.Sequence()
+			distinct := map[string]bool{}
+			composition := map[byte]int{}
+			for p := range seq {
+				if e.isSentinel(p) {
+					continue
+				}
+				composition[seq[p]]++
+				for q := p + 1; q <= len(seq) && !e.isSentinel(q-1); q++ {
+					distinct[string(seq[p:q])] = true
+				}
+			}
+			if st.DistinctSubstrings != len(distinct) {
+				t.Errorf("DistinctSubstrings = %d, want %d", st.DistinctSubstrings, len(distinct))
+			}
+			if fmt.Sprint(st.Composition) != fmt.Sprint(composition) {
+				t.Errorf("Composition = %v, want %v", st.Composition, composition)
+			}
+			intervals := 0
+			var dfs func(iv EsaInterval)
+			dfs = func(iv EsaInterval) {
+				intervals++
+				for _, c := range e.Children(iv) {
+					if c.start != c.end {
+						dfs(c)
+					}
+				}
+			}
+			dfs(e.Root())
+			if st.Intervals != intervals {
+				t.Errorf("Intervals = %d, want %d", st.Intervals, intervals)
+			}
+			maxLcp, sum := 0, 0
+			for _, l := range e.Lcp()[1:len(seq)] {
+				sum += l
+				if l > maxLcp {
+					maxLcp = l
+				}
+			}
+			if st.MaxLcp != maxLcp || st.MeanLcp != float64(sum)/float64(len(seq)-1) {
+				t.Errorf("lcp max/mean = %d/%f, want %d/%f", st.MaxLcp, st.MeanLcp, maxLcp, float64(sum)/float64(len(seq)-1))
+			}
+			if st.Length != len(seq) || st.StrandSize != e.StrandSize() || st.Alphabet != "Bytes" {
+				t.Errorf("Stats = %+v", st)
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package esaMatcher
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TableColumn selects the columns written by Esa.WriteTable. Columns can be combined with |.
+type TableColumn int
+
+const (
+	// The suffix array.
+	ColSA TableColumn = 1 << iota
+	// The inverse suffix array, indexed by the row like the other arrays.
+	ColISA
+	// The lcp array.
+	ColLCP
+	// The child table.
+	ColCLD
+	// The Burrows-Wheeler transform, that is the character before each suffix.
+	ColBWT
+	// The suffixes themselves.
+	ColSuffix
+
+	// The columns written if none are selected.
+	DefaultColumns = ColSA | ColLCP | ColCLD | ColSuffix
+)
+
+// TableFormat selects the output format of Esa.WriteTable.
+type TableFormat int
+
+const (
+	// Tab separated values with a header line.
+	TableTSV TableFormat = iota
+	// A Markdown table.
+	TableMarkdown
+	// A JSON array with an object per row.
+	TableJSON
+)
+
+// TableOptions control Esa.WriteTable.
+type TableOptions struct {
+	// The rows From up to, but not including, To are written.
+	// If To is 0 or larger than the number of suffixes, the rows up to the end are written.
+	From, To int
+	// Suffixes longer than Width are truncated and marked by "...". 0 means no limit.
+	Width int
+	// The columns to write, DefaultColumns if 0. The row number is always written.
+	Columns TableColumn
+	// The output format.
+	Format TableFormat
+}
+
+// The names and JSON keys of the columns in the order they are written.
+var tableColumns = []struct {
+	col       TableColumn
+	name, key string
+}{
+	{ColSA, "SA", "sa"}, {ColISA, "ISA", "isa"}, {ColLCP, "LCP", "lcp"},
+	{ColCLD, "CLD", "cld"}, {ColBWT, "BWT", "bwt"}, {ColSuffix, "S[SA[i]..]", "suffix"},
+}
+
+// WriteTable writes the arrays of the ESA as a table to w, one row per suffix.
+// Bytes that are not printable ASCII characters, like out-of-band sentinels of binary
+// texts, are written as \xhh.
+func (e *Esa) WriteTable(w io.Writer, opts TableOptions) error {
+	n := len(e.sa)
+	if opts.To <= 0 || opts.To > n {
+		opts.To = n
+	}
+	if opts.From < 0 || opts.From > opts.To {
+		return fmt.Errorf("invalid row range [%d,%d)", opts.From, opts.To)
+	}
+	if opts.Columns == 0 {
+		opts.Columns = DefaultColumns
+	}
+	var isa []int
+	if opts.Columns&ColISA != 0 {
+		if isa = e.Isa(); isa == nil {
+			isa = Isa(e.sa)
+		}
+	}
+	header, keys := []string{"i"}, []string{"i"}
+	for _, c := range tableColumns {
+		if opts.Columns&c.col != 0 {
+			header = append(header, c.name)
+			keys = append(keys, c.key)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	switch opts.Format {
+	case TableTSV:
+		bw.WriteString(strings.Join(header, "\t") + "\n")
+	case TableMarkdown:
+		bw.WriteString("| " + strings.Join(header, " | ") + " |\n")
+		bw.WriteString(strings.Repeat("|---", len(header)) + "|\n")
+	case TableJSON:
+		bw.WriteString("[")
+	default:
+		return fmt.Errorf("unknown table format %d", opts.Format)
+	}
+	row := make([]string, 0, len(header))
+	for i := opts.From; i < opts.To; i++ {
+		row = append(row[:0], strconv.Itoa(i))
+		for _, c := range tableColumns {
+			if opts.Columns&c.col == 0 {
+				continue
+			}
+			switch c.col {
+			case ColSA:
+				row = append(row, strconv.Itoa(e.sa[i]))
+			case ColISA:
+				row = append(row, strconv.Itoa(isa[i]))
+			case ColLCP:
+				row = append(row, strconv.Itoa(e.lcp[i]))
+			case ColCLD:
+				row = append(row, strconv.Itoa(e.cld[i]))
+			case ColBWT:
+				row = append(row, printable(e.s[(e.sa[i]+n-1)%n:][:1]))
+			case ColSuffix:
+				suffix, trunc := e.s[e.sa[i]:], ""
+				if opts.Width > 0 && len(suffix) > opts.Width {
+					suffix, trunc = suffix[:opts.Width], "..."
+				}
+				row = append(row, printable(suffix)+trunc)
+			}
+		}
+		switch opts.Format {
+		case TableTSV:
+			bw.WriteString(strings.Join(row, "\t") + "\n")
+		case TableMarkdown:
+			for k := range row {
+				row[k] = strings.ReplaceAll(row[k], "|", `\|`)
+			}
+			bw.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		case TableJSON:
+			if i > opts.From {
+				bw.WriteString(",")
+			}
+			bw.WriteString("\n{")
+			for k, v := range row {
+				if k > 0 {
+					bw.WriteString(",")
+				}
+				bw.WriteString(strconv.Quote(keys[k]) + ":")
+				if keys[k] == "bwt" || keys[k] == "suffix" {
+					q, _ := json.Marshal(v)
+					v = string(q)
+				}
+				bw.WriteString(v)
+			}
+			bw.WriteString("}")
+		}
+	}
+	if opts.Format == TableJSON {
+		bw.WriteString("\n]\n")
+	}
+	return bw.Flush()
+}
+
+// Return s with all bytes that are not printable ASCII characters escaped as \xhh.
+func printable(s []byte) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c >= ' ' && c <= '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, `\x%02x`, c)
+		}
+	}
+	return b.String()
+}
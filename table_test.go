@@ -0,0 +1,70 @@
+package esaMatcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteTable(t *testing.T) {
+	e := NewEsa([]byte("ACAAACATAT"), "") //OhleBusch Book
+	for _, c := range []struct {
+		opts TableOptions
+		want string
+	}{
+		{TableOptions{From: 0, To: 3},
+			"i\tSA\tLCP\tCLD\tS[SA[i]..]\n" +
+				"0\t10\t-1\t11\t$\n" +
+				"1\t2\t0\t7\tAAACATAT$\n" +
+				"2\t3\t2\t2\tAACATAT$\n"},
+		{TableOptions{From: 9, Width: 3, Columns: ColISA | ColBWT | ColSuffix},
+			"i\tISA\tBWT\tS[SA[i]..]\n" +
+				"9\t9\tA\tT$\n" +
+				"10\t0\tA\tTAT...\n"},
+		{TableOptions{From: 1, To: 2, Width: 4, Format: TableMarkdown},
+			"| i | SA | LCP | CLD | S[SA[i]..] |\n" +
+				"|---|---|---|---|---|\n" +
+				"| 1 | 2 | 0 | 7 | AAAC... |\n"},
+	} {
+		var buf bytes.Buffer
+		if err := e.WriteTable(&buf, c.opts); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != c.want {
+			t.Errorf("WriteTable(%+v) = %q, want %q", c.opts, buf.String(), c.want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := e.WriteTable(&buf, TableOptions{Format: TableJSON, Columns: ColSA | ColBWT | ColSuffix}); err != nil {
+		t.Fatal(err)
+	}
+	var rows []struct {
+		I      int    `json:"i"`
+		SA     int    `json:"sa"`
+		BWT    string `json:"bwt"`
+		Suffix string `json:"suffix"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("WriteTable wrote invalid JSON %q: %v", buf.String(), err)
+	}
+	for i, r := range rows {
+		p := e.Sa()[i]
+		if r.I != i || r.SA != p || r.Suffix != string(e.Sequence()[p:]) {
+			t.Errorf("row %d = %+v", i, r)
+		}
+	}
+
+	b, err := NewAlphabetEsa([]byte("a|\x00"), Bytes, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	b.WriteTable(&buf, TableOptions{Format: TableMarkdown, Columns: ColSuffix})
+	if want := `| 2 | a\|\x00$ |`; !bytes.Contains(buf.Bytes(), []byte(want)) {
+		t.Errorf("WriteTable of binary text = %q, does not contain %q", buf.String(), want)
+	}
+	if err := e.WriteTable(&buf, TableOptions{From: 5, To: 2}); err == nil {
+		t.Errorf("WriteTable with an invalid range did not fail")
+	}
+}
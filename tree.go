@@ -0,0 +1,79 @@
+package esaMatcher
+
+// Children returns the child intervals of the lcp-interval iv in lexicographic order.
+// Singleton intervals, the leaves of the tree, have no children.
+//
+// Together with Root, Parent and Depth, the ESA can be traversed like a suffix tree
+// whose inner nodes are the lcp-intervals and whose leaves are the suffixes.
+func (e *Esa) Children(iv EsaInterval) []EsaInterval {
+	if iv.start == iv.end {
+		return nil
+	}
+	var children []EsaInterval
+	e.eachChild(iv, func(c EsaInterval) {
+		children = append(children, c)
+	})
+	return children
+}
+
+// eachChild calls f for every child interval of the lcp-interval i in lexicographic order.
+func (e *Esa) eachChild(i EsaInterval, f func(EsaInterval)) {
+	lower := i.start
+	upper := i.mid
+	for e.lcp[upper] == i.l {
+		f(e.Interval(lower, upper-1))
+		lower = upper
+		if lower == i.end {
+			break
+		}
+		upper = e.cld[upper]
+	}
+	f(e.Interval(lower, i.end))
+}
+
+// Parent returns the smallest lcp-interval that encloses iv.
+// The parent of the root is the empty interval.
+//
+// The lcp value of the parent is the larger of the lcp values at the bounds of iv.
+// Its bounds are found by scanning the lcp array or, with WithSuffixLinks or WithLCE,
+// in O(log n) time.
+func (e *Esa) Parent(iv EsaInterval) EsaInterval {
+	l := e.lcp[iv.start]
+	if e.lcp[iv.end+1] > l {
+		l = e.lcp[iv.end+1]
+	}
+	if l < 0 {
+		return EmptyEsaInterval()
+	}
+	return e.Interval(e.parentBounds(iv.start, iv.end, l))
+}
+
+// Depth returns the length of the string that all suffixes of iv share, that is the
+// lcp value of an lcp-interval. The depth of a leaf is the length of its suffix up to
+// and including the first sentinel.
+func (e *Esa) Depth(iv EsaInterval) int {
+	if iv.start != iv.end {
+		return iv.l
+	}
+	p := e.sa[iv.start]
+	return e.sentinelAfter(p) - p + 1
+}
+
+// Label returns the string that all suffixes of iv share, see Depth.
+// The returned slice shares its memory with the sequence of the ESA.
+func (e *Esa) Label(iv EsaInterval) []byte {
+	p := e.sa[iv.start]
+	return e.s[p : p+e.Depth(iv)]
+}
+
+// EdgeLabel returns the label of the edge from the parent of iv to iv,
+// that is the part of Label(iv) behind the label of the parent.
+// The edge label of the root is empty.
+func (e *Esa) EdgeLabel(iv EsaInterval) []byte {
+	label := e.Label(iv)
+	parent := e.Parent(iv)
+	if parent.start == -1 {
+		return label[:0]
+	}
+	return label[parent.l:]
+}
@@ -0,0 +1,69 @@
+package esaMatcher
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		[]byte("A$C#AC$"),
+		ranseq(500, "ACG"),
+	}
+	for _, s := range seqs {
+		for _, e := range []Esa{NewEsa(s, ""), NewRevEsa(s, "")} {
+			for _, ne := range []Esa{e, e.WithLCE()} {
+				root := ne.Root()
+				if p := ne.Parent(root); p.Start() != -1 {
+					t.Errorf("Parent(root) = %v, want empty interval", p)
+				}
+				if l := ne.EdgeLabel(root); len(l) != 0 {
+					t.Errorf("EdgeLabel(root) = %q, want empty", l)
+				}
+				leaves := 0
+				var dfs func(iv EsaInterval)
+				dfs = func(iv EsaInterval) {
+					children := ne.Children(iv)
+					if iv.start == iv.end {
+						leaves++
+						p := ne.sa[iv.start]
+						if d := ne.Depth(iv); d != ne.LCE(p, p)+1 {
+							t.Errorf("Depth(%v) = %d, want %d", iv, d, ne.LCE(p, p)+1)
+						}
+						if children != nil {
+							t.Errorf("leaf %v has children %v", iv, children)
+						}
+						return
+					}
+					if len(children) < 2 {
+						t.Errorf("%v has %d children", iv, len(children))
+					}
+					if children[0].start != iv.start || children[len(children)-1].end != iv.end {
+						t.Errorf("children of %v do not cover it: %v", iv, children)
+					}
+					label := ne.Label(iv)
+					for k, c := range children {
+						if p := ne.Parent(c); p != iv {
+							t.Errorf("Parent(%v) = %v, want %v", c, p, iv)
+						}
+						cl := ne.Label(c)
+						if want := append(append([]byte{}, label...), ne.EdgeLabel(c)...); !bytes.Equal(cl, want) {
+							t.Errorf("Label(%v) = %q, want %q", c, cl, want)
+						}
+						// The children partition the interval in suffix array order.
+						if k > 0 && children[k-1].end+1 != c.start {
+							t.Errorf("children of %v are not adjacent: %v", iv, children)
+						}
+						dfs(c)
+					}
+				}
+				dfs(root)
+				if leaves != len(ne.Sa()) {
+					t.Errorf("found %d leaves, want %d", leaves, len(ne.Sa()))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package esaMatcher
+
+import "fmt"
+
+// ValidationLevel selects the checks done by Esa.Validate.
+type ValidationLevel int
+
+const (
+	// ValidateFast checks the sizes and bounds of all arrays, that the suffix array is a
+	// permutation and that neighbouring suffixes are ordered by the character following
+	// their common prefix. It runs in linear time and needs n bits of additional memory.
+	ValidateFast ValidationLevel = iota
+	// ValidateFull additionally recomputes the lcp and the child array and compares them
+	// to the arrays of the ESA. Together with the fast checks this guarantees that
+	// the suffix array is sorted. It runs in linear time but needs two additional
+	// arrays of size n.
+	ValidateFull
+)
+
+// A ValidationError describes the first inconsistency found by Esa.Validate.
+type ValidationError struct {
+	// Name of the inconsistent array, that is "s", "sa", "lcp" or "cld".
+	Array string
+	// Index of the first violating element in Array.
+	Index int
+	// Description of the violation.
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid esa: %s[%d]: %s", e.Array, e.Index, e.Reason)
+}
+
+// Validate checks the consistency of the sequence, suffix array, lcp and child array
+// of the ESA with the given level of detail.
+// If an inconsistency is found, a *ValidationError naming the first violating index is returned.
+// This is intended for ESAs that were loaded from disk or constructed by other means than NewEsa.
+//
+// Concept
+//
+// In the fast mode, the first character after the common prefix of two neighbouring
+// suffixes in the suffix array must differ and be smaller for the upper suffix. In the full mode,
+// the lcp array is recomputed from the suffix array by the algorithm of Kasai et al. that
+// also backs Lcp. If both agree, all suffixes are sorted. Finally, the child array is
+// recomputed from the lcp array, which also covers the invariants on the child intervals
+// that hold for a valid child array.
+func (e *Esa) Validate(level ValidationLevel) error {
+	n := len(e.s)
+	fail := func(array string, i int, format string, a ...interface{}) error {
+		return &ValidationError{array, i, fmt.Sprintf(format, a...)}
+	}
+
+	// Sizes and sentinels
+	if n == 0 {
+		return fail("s", 0, "sequence is empty, it must at least contain the sentinel")
+	}
+	if len(e.sa) != n {
+		return fail("sa", len(e.sa), "length is %d, want %d", len(e.sa), n)
+	}
+	if len(e.lcp) != n+1 {
+		return fail("lcp", len(e.lcp), "length is %d, want %d", len(e.lcp), n+1)
+	}
+	if len(e.cld) != n+1 {
+		return fail("cld", len(e.cld), "length is %d, want %d", len(e.cld), n+1)
+	}
+	if e.s[n-1] != '$' {
+		return fail("s", n-1, "is %q, want sentinel '$'", e.s[n-1])
+	}
+	if e.strandSize < 0 || e.strandSize > n-1 {
+		return fail("s", e.strandSize, "strand size %d is out of range", e.strandSize)
+	}
+	if e.strandSize < n-1 {
+		if e.s[e.strandSize] != '#' {
+			return fail("s", e.strandSize, "is %q, want sentinel '#'", e.s[e.strandSize])
+		}
+		if n != 2*e.strandSize+2 {
+			return fail("s", n, "length does not match two strands of size %d", e.strandSize)
+		}
+	}
+
+	// The suffix array is a permutation
+	seen := make([]bool, n)
+	for i, p := range e.sa {
+		if p < 0 || p >= n {
+			return fail("sa", i, "position %d is out of range", p)
+		}
+		if seen[p] {
+			return fail("sa", i, "position %d occurs twice", p)
+		}
+		seen[p] = true
+	}
+
+	// Bounds of lcp and cld and the order of neighbouring suffixes
+	if e.lcp[0] != -1 {
+		return fail("lcp", 0, "is %d, want -1", e.lcp[0])
+	}
+	if e.lcp[n] != -1 {
+		return fail("lcp", n, "is %d, want -1", e.lcp[n])
+	}
+	for i := 1; i < n; i++ {
+		p, q, l := e.sa[i-1], e.sa[i], e.lcp[i]
+		if l < 0 || p+l >= n || q+l >= n {
+			return fail("lcp", i, "value %d is out of range", l)
+		}
+		// Record separators are equal bytes but distinct sentinels.
+		if kp, kq := e.suffixKey(p+l), e.suffixKey(q+l); kp == kq && !e.isSentinel(q+l) {
+			return fail("lcp", i, "suffixes %d and %d share more than %d characters", p, q, l)
+		} else if kp > kq {
+			return fail("sa", i, "suffix %d is not larger than suffix %d", q, p)
+		}
+	}
+	for i, c := range e.cld {
+		if c < 0 || c > n {
+			return fail("cld", i, "child %d is out of range", c)
+		}
+	}
+	if level < ValidateFull {
+		return nil
+	}
+
+	// Recompute lcp and cld
+	isa := make([]int, n)
+	for i, p := range e.sa {
+		isa[p] = i
+	}
+	l := 0
+	for i := 0; i < n; i++ {
+		j := isa[i]
+		if j == 0 {
+			continue
+		}
+		k := e.sa[j-1]
+		for k+l < n && i+l < n && !e.isSentinel(i+l) && e.suffixKey(k+l) == e.suffixKey(i+l) {
+			l++
+		}
+		if e.lcp[j] != l {
+			return fail("lcp", j, "is %d, want %d", e.lcp[j], l)
+		}
+		l -= 1
+		if l < 0 {
+			l = 0
+		}
+	}
+	for i, c := range Cld(e.lcp) {
+		if e.cld[i] != c {
+			return fail("cld", i, "is %d, want %d", e.cld[i], c)
+		}
+	}
+	return nil
+}
+
+// Return the key by which the character at position p of the sequence is sorted.
+// The sentinels are unique and sort lower than all other characters, as they do in
+// the construction of the ESA.
+func (e *Esa) suffixKey(p int) int {
+	switch {
+	case p == len(e.s)-1:
+		return 1
+	case p == e.strandSize:
+		return 0
+	}
+	return int(e.s[p]) + 2
+}
@@ -0,0 +1,57 @@
+package esaMatcher
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	seqs := [][]byte{
+		[]byte("ACAAACATAT"), //OhleBusch Book
+		[]byte("ACTTCACAAA"), //ranseq
+		[]byte("AAGTAAGG"),   //phylonium & andi
+		[]byte("a$b#c\x00a$b\xffa$b#"),
+		ranseq50KBP,
+	}
+	for _, seq := range seqs {
+		for _, e := range []Esa{NewEsa(seq, ""), NewRevEsa(seq, "")} {
+			for _, level := range []ValidationLevel{ValidateFast, ValidateFull} {
+				if err := e.Validate(level); err != nil {
+					t.Errorf("Valid ESA of %.20q rejected: %v", seq, err)
+				}
+			}
+		}
+	}
+}
+
+func TestValidate_Corrupt(t *testing.T) {
+	seq := []byte("ACAAACATAT")
+	tests := []struct {
+		name    string
+		corrupt func(e *Esa)
+		level   ValidationLevel
+		array   string
+		index   int
+	}{
+		{"duplicate", func(e *Esa) { e.sa[3] = e.sa[4] }, ValidateFast, "sa", 4},
+		{"unsorted", func(e *Esa) { e.sa[3], e.sa[4] = e.sa[4], e.sa[3] }, ValidateFast, "sa", 4},
+		{"lcp bound", func(e *Esa) { e.lcp[0] = 0 }, ValidateFast, "lcp", 0},
+		{"lcp too small", func(e *Esa) { e.lcp[5]-- }, ValidateFast, "lcp", 5},
+		{"cld", func(e *Esa) { e.cld[1], e.cld[2] = e.cld[2], e.cld[1] }, ValidateFull, "cld", 1},
+		{"sentinel", func(e *Esa) { e.s[len(e.s)-1] = 'A' }, ValidateFast, "s", 10},
+		{"length", func(e *Esa) { e.lcp = e.lcp[:len(e.lcp)-1] }, ValidateFast, "lcp", 11},
+	}
+	for _, test := range tests {
+		e := NewEsa(seq, "")
+		test.corrupt(&e)
+		err := e.Validate(test.level)
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("%s: got %v, want a ValidationError", test.name, err)
+			continue
+		}
+		if verr.Array != test.array || verr.Index != test.index {
+			t.Errorf("%s: got %v, want error in %s[%d]", test.name, err, test.array, test.index)
+		}
+	}
+}